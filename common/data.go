@@ -19,3 +19,9 @@ func IntPoller(fn func() (value int, disabled, ok bool)) func() (Int, bool) {
 		return Int{Disabled: disabled, Value: value}, ok
 	}
 }
+
+type Bool bool
+
+func (b Bool) Equal(other Bool) bool {
+	return b == other
+}
@@ -0,0 +1,110 @@
+package common
+
+import "sync"
+
+// Events fans out events to subscribers. Contrary to Pubsub, it does not keep
+// a current value: every published event is sent to all subscribers.
+type Events[T any] struct {
+	mu          sync.Mutex
+	subscribers map[any]*eventsQueue[T]
+}
+
+func NewEvents[T any]() *Events[T] {
+	return &Events[T]{
+		subscribers: map[any]*eventsQueue[T]{},
+	}
+}
+
+// eventsQueue is the unbounded queue of events of a subscriber, drained by
+// its own goroutine, so that publishing neither blocks nor drops events.
+type eventsQueue[T any] struct {
+	mu     sync.Mutex
+	events []T
+	closed bool
+	wake   chan struct{}
+}
+
+func (q *eventsQueue[T]) push(event T) {
+	q.mu.Lock()
+	q.events = append(q.events, event)
+	q.mu.Unlock()
+
+	q.signal()
+}
+
+func (q *eventsQueue[T]) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	q.signal()
+}
+
+func (q *eventsQueue[T]) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run calls callback for each event, until the queue is closed. Events queued
+// before the queue is closed are still delivered.
+func (q *eventsQueue[T]) run(callback func(T)) {
+	for range q.wake {
+		q.mu.Lock()
+		events := q.events
+		closed := q.closed
+		q.events = nil
+		q.mu.Unlock()
+
+		for _, event := range events {
+			callback(event)
+		}
+
+		if closed {
+			return
+		}
+	}
+}
+
+func (e *Events[T]) Subscribe(id any, callback func(T)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.subscribers[id]; ok {
+		// Already subscribed
+		return
+	}
+
+	q := &eventsQueue[T]{
+		wake: make(chan struct{}, 1),
+	}
+
+	if id == nil {
+		id = q
+	}
+
+	e.subscribers[id] = q
+
+	go q.run(callback)
+}
+
+func (e *Events[T]) Unsubscribe(id any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if q, ok := e.subscribers[id]; ok {
+		delete(e.subscribers, id)
+		q.close()
+	}
+}
+
+// Publish queues event for all subscribers.
+func (e *Events[T]) Publish(event T) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, q := range e.subscribers {
+		q.push(event)
+	}
+}
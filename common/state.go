@@ -0,0 +1,28 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// StateDir returns the directory where swaypanion keeps its persistent state,
+// creating it if needed.
+func StateDir() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+
+	dir := filepath.Join(stateHome, "swaypanion")
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
@@ -0,0 +1,43 @@
+package config
+
+type App struct {
+	Window     WindowIdentification `yaml:"window"`
+	Launch     Command              `yaml:"launch"`
+	Workspace  string               `yaml:"workspace"`
+	Floating   bool                 `yaml:"floating"`
+	Scratchpad bool                 `yaml:"scratchpad"`
+}
+
+type Apps struct {
+	config[*Apps] `yaml:"-"`
+
+	Apps map[string]App `yaml:",inline"`
+}
+
+var DefaultApps = &Apps{
+	Apps: map[string]App{
+		"spotify": {
+			Window: WindowIdentification{Type: WindowMatchInstance, Match: "spotify"},
+			Launch: Command{
+				Type:    CommandTypeShell,
+				Command: "/usr/bin/spotify",
+			},
+		},
+	},
+}
+
+func (a *Apps) applyDefault() {
+	if len(a.Apps) == 0 {
+		a.Apps = make(map[string]App, len(DefaultApps.Apps))
+		for name, app := range DefaultApps.Apps {
+			a.Apps[name] = app
+		}
+	}
+
+	for name, app := range a.Apps {
+		if app.Launch.Type == "" {
+			app.Launch.Type = CommandTypeShell
+			a.Apps[name] = app
+		}
+	}
+}
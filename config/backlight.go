@@ -31,6 +31,8 @@ var DefaultBacklight = &Backlight{
 			"  {value} %",
 			"  {value} %",
 		},
+		StackTag:      "swaypanion-brightness",
+		SuppressOnDND: &trueValue,
 	},
 }
 
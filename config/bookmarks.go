@@ -0,0 +1,27 @@
+package config
+
+type Bookmarks struct {
+	config[*Bookmarks] `yaml:"-"`
+
+	// Launch commands per bookmark key, used when the bookmarked window has
+	// been closed. If there is no command for a key, the app the window
+	// belonged to is launched.
+	Launch map[string]Command `yaml:"launch"`
+}
+
+var DefaultBookmarks = &Bookmarks{
+	Launch: map[string]Command{},
+}
+
+func (b *Bookmarks) applyDefault() {
+	if b.Launch == nil {
+		b.Launch = map[string]Command{}
+	}
+
+	for key, command := range b.Launch {
+		if command.Type == "" {
+			command.Type = CommandTypeShell
+			b.Launch[key] = command
+		}
+	}
+}
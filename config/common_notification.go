@@ -2,9 +2,20 @@ package config
 
 import "time"
 
+// NotificationAction is an action offered by a notification, which runs a
+// swaypanion socket command (eg. "volume set:20") and/or a command.
+type NotificationAction struct {
+	Label    string  `yaml:"label"`
+	Socket   string  `yaml:"socket"`
+	Command  Command `yaml:",inline"`
+	MaxValue *int    `yaml:"max_value,omitempty"`
+}
+
 type NotificationSectionMessage struct {
-	Enabled *bool         `yaml:"enabled"`
-	Timeout time.Duration `yaml:"timeout"`
+	Enabled       *bool                           `yaml:"enabled"`
+	Timeout       time.Duration                   `yaml:"timeout"`
+	SuppressOnDND *bool                           `yaml:"suppress_on_dnd"`
+	Actions       map[string][]NotificationAction `yaml:"actions"`
 }
 
 func (n NotificationSectionMessage) applyDefault(def NotificationSectionMessage) NotificationSectionMessage {
@@ -16,15 +27,35 @@ func (n NotificationSectionMessage) applyDefault(def NotificationSectionMessage)
 		n.Timeout = def.Timeout
 	}
 
+	if n.SuppressOnDND == nil {
+		n.SuppressOnDND = def.SuppressOnDND
+	}
+
+	if len(n.Actions) == 0 {
+		n.Actions = make(map[string][]NotificationAction, len(def.Actions))
+		for k, v := range def.Actions {
+			n.Actions[k] = v
+		}
+	}
+
 	return n
 }
 
 type NotificationSectionPercent struct {
-	Enabled        *bool         `yaml:"enabled"`
-	Timeout        time.Duration `yaml:"timeout"`
-	FormatDisabled string        `yaml:"format_disabled"`
-	Format0        string        `yaml:"format0"`
-	Formats        []string      `yaml:"formats"`
+	Enabled        *bool                `yaml:"enabled"`
+	Timeout        time.Duration        `yaml:"timeout"`
+	FormatDisabled string               `yaml:"format_disabled"`
+	Format0        string               `yaml:"format0"`
+	Formats        []string             `yaml:"formats"`
+	IconDisabled   string               `yaml:"icon_disabled"`
+	Icon0          string               `yaml:"icon0"`
+	Icons          []string             `yaml:"icons"`
+	Urgency        string               `yaml:"urgency"`
+	Category       string               `yaml:"category"`
+	StackTag       string               `yaml:"stack_tag"`
+	SoundName      string               `yaml:"sound_name"`
+	SuppressOnDND  *bool                `yaml:"suppress_on_dnd"`
+	Actions        []NotificationAction `yaml:"actions"`
 }
 
 func (n NotificationSectionPercent) applyDefault(def NotificationSectionPercent) NotificationSectionPercent {
@@ -49,5 +80,43 @@ func (n NotificationSectionPercent) applyDefault(def NotificationSectionPercent)
 		copy(n.Formats, def.Formats)
 	}
 
+	if n.IconDisabled == "" {
+		n.IconDisabled = def.IconDisabled
+	}
+
+	if n.Icon0 == "" {
+		n.Icon0 = def.Icon0
+	}
+
+	if len(n.Icons) == 0 {
+		n.Icons = make([]string, len(def.Icons))
+		copy(n.Icons, def.Icons)
+	}
+
+	if n.Urgency == "" {
+		n.Urgency = def.Urgency
+	}
+
+	if n.Category == "" {
+		n.Category = def.Category
+	}
+
+	if n.StackTag == "" {
+		n.StackTag = def.StackTag
+	}
+
+	if n.SoundName == "" {
+		n.SoundName = def.SoundName
+	}
+
+	if n.SuppressOnDND == nil {
+		n.SuppressOnDND = def.SuppressOnDND
+	}
+
+	if len(n.Actions) == 0 {
+		n.Actions = make([]NotificationAction, len(def.Actions))
+		copy(n.Actions, def.Actions)
+	}
+
 	return n
 }
@@ -2,7 +2,10 @@ package config
 
 import "sync"
 
-var trueValue = true
+var (
+	trueValue  = true
+	falseValue = false
+)
 
 type config[T any] struct {
 	mu              sync.Mutex
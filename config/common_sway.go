@@ -1,10 +1,16 @@
 package config
 
 import (
+	"errors"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/joshuarubin/go-sway"
+
+	"github.com/willoma/swaypanion/common"
 )
 
 type WindowMatchType string
@@ -19,93 +25,297 @@ const (
 	WindowMatchInstance   WindowMatchType = "instance"
 	WindowMatchWindowRole WindowMatchType = "window_role"
 	WindowMatchWindowType WindowMatchType = "window_type"
+	WindowMatchMarks      WindowMatchType = "marks"
+	WindowMatchWorkspace  WindowMatchType = "workspace"
+	WindowMatchFloating   WindowMatchType = "floating"
+	WindowMatchUrgent     WindowMatchType = "urgent"
 )
 
+// WindowIdentification matches windows on a property, on a list of criteria
+// which must all match, and on a list of criteria among which at least one
+// must match. Not negates the result.
 type WindowIdentification struct {
-	Type    WindowMatchType `yaml:"type"`
-	Partial bool            `yaml:"partial"`
-	Match   string          `yaml:"match"`
+	Type    WindowMatchType        `yaml:"type,omitempty" json:"type,omitempty"`
+	Partial bool                   `yaml:"partial,omitempty" json:"partial,omitempty"`
+	Regex   bool                   `yaml:"regex,omitempty" json:"regex,omitempty"`
+	Not     bool                   `yaml:"not,omitempty" json:"not,omitempty"`
+	Match   string                 `yaml:"match,omitempty" json:"match,omitempty"`
+	All     []WindowIdentification `yaml:"all,omitempty" json:"all,omitempty"`
+	Any     []WindowIdentification `yaml:"any,omitempty" json:"any,omitempty"`
 }
 
-func (w WindowIdentification) MatchWindow(win *sway.Node) bool {
-	if win == nil {
-		return false
+var (
+	ErrInvalidWindowIdentification = errors.New("window identification must be <type>=<match>, <type>~=<match> or <type>=/<regex>/, optionally negated with !, separated by commas")
+	ErrUnknownWindowMatchType      = errors.New("unknown window match type")
+	ErrEmptyWindowIdentification   = errors.New("window identification needs a type, all or any")
+	ErrPartialAndRegex             = errors.New("partial and regex cannot be combined")
+	ErrPartialNotSupported         = errors.New("partial match not supported for this type")
+	ErrRegexNotSupported           = errors.New("regex match not supported for this type")
+	ErrInvalidMatchValue           = errors.New("invalid match value")
+
+	windowRegexpsMu sync.Mutex
+	windowRegexps   = map[string]*regexp.Regexp{}
+)
+
+// ParseWindowIdentification reads a window identification from its textual
+// form: "<type>=<match>" for a complete match, "<type>~=<match>" for a
+// partial match, "<type>=/<regex>/" for a regex match. A "!" before the
+// type negates the criterion, criteria separated by commas must all match.
+// Commas inside a regex do not separate criteria, other commas in a match
+// must be escaped as "\,".
+func ParseWindowIdentification(str string) (WindowIdentification, error) {
+	var criteria []WindowIdentification
+
+	for _, criterion := range splitWindowCriteria(str) {
+		w, err := parseWindowCriterion(criterion)
+		if err != nil {
+			return WindowIdentification{}, err
+		}
+
+		criteria = append(criteria, w)
 	}
 
-	if w.Partial {
-		return w.matchPartialWindow(win)
+	if len(criteria) == 1 {
+		return criteria[0], nil
 	}
 
-	return w.matchCompleteWindow(win)
+	return WindowIdentification{All: criteria}, nil
+}
+
+// splitWindowCriteria splits str on commas, except escaped ones and the ones
+// inside a regex, which ends at a slash followed by a comma or by the end of
+// str.
+func splitWindowCriteria(str string) []string {
+	var (
+		criteria []string
+		current  []byte
+		// regexStart is the index of the opening slash in current, -1
+		// outside of a regex
+		regexStart = -1
+	)
+
+	for i := 0; i < len(str); i++ {
+		c := str[i]
 
+		switch {
+		case regexStart == -1 && c == '\\' && i+1 < len(str) && str[i+1] == ',':
+			current = append(current, ',')
+			i++
+
+			continue
+		case c == ',' && (regexStart == -1 || len(current)-1 > regexStart && current[len(current)-1] == '/'):
+			criteria = append(criteria, string(current))
+			current = nil
+			regexStart = -1
+
+			continue
+		case regexStart == -1 && c == '/' && len(current) > 0 && strings.IndexByte(string(current), '=') == len(current)-1:
+			regexStart = len(current)
+		}
+
+		current = append(current, c)
+	}
+
+	return append(criteria, string(current))
 }
 
-func (w WindowIdentification) matchCompleteWindow(win *sway.Node) bool {
+func parseWindowCriterion(str string) (WindowIdentification, error) {
+	key, match, found := strings.Cut(str, "=")
+	if !found || match == "" {
+		return WindowIdentification{}, ErrInvalidWindowIdentification
+	}
+
+	key = strings.TrimSpace(key)
+
+	var w WindowIdentification
+
+	key, w.Not = strings.CutPrefix(key, "!")
+	key, w.Partial = strings.CutSuffix(key, "~")
+
+	w.Type = WindowMatchType(strings.TrimSpace(key))
+
+	if len(match) > 1 && strings.HasPrefix(match, "/") && strings.HasSuffix(match, "/") {
+		w.Regex = true
+		match = match[1 : len(match)-1]
+	}
+
+	w.Match = match
+
+	if err := w.Validate(); err != nil {
+		return WindowIdentification{}, err
+	}
+
+	return w, nil
+}
+
+// Validate checks the identification can be used to match windows.
+func (w WindowIdentification) Validate() error {
+	if w.Type == "" && len(w.All) == 0 && len(w.Any) == 0 {
+		return ErrEmptyWindowIdentification
+	}
+
+	for _, sub := range slices.Concat(w.All, w.Any) {
+		if err := sub.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if w.Type == "" {
+		return nil
+	}
+
+	if w.Partial && w.Regex {
+		return ErrPartialAndRegex
+	}
+
 	switch w.Type {
-	case WindowMatchAppID:
-		return win.AppID != nil && *win.AppID == w.Match
-	case WindowMatchPID:
-		pid, err := strconv.ParseUint(w.Match, 10, 32)
-		if err != nil {
-			return false
+	case WindowMatchAppID, WindowMatchShell, WindowMatchTitle, WindowMatchClass, WindowMatchInstance,
+		WindowMatchWindowRole, WindowMatchWindowType, WindowMatchMarks, WindowMatchWorkspace:
+	case WindowMatchPID, WindowMatchWindowID:
+		if w.Partial {
+			return common.Errorf(string(w.Type), ErrPartialNotSupported)
 		}
 
-		return win.PID != nil && *win.PID == uint32(pid)
-	case WindowMatchShell:
-		return win.Shell != nil && *win.Shell == w.Match
-	case WindowMatchWindowID:
-		wid, err := strconv.ParseInt(w.Match, 10, 64)
-		if err != nil {
-			return false
+		if !w.Regex {
+			if _, err := strconv.ParseInt(w.Match, 10, 64); err != nil {
+				return common.Errorf(string(w.Type)+" "+w.Match, ErrInvalidMatchValue)
+			}
+		}
+	case WindowMatchFloating, WindowMatchUrgent:
+		if w.Partial {
+			return common.Errorf(string(w.Type), ErrPartialNotSupported)
 		}
 
-		return win.Window != nil && *win.Window == wid
-	case WindowMatchTitle:
-		return win.WindowProperties != nil && win.WindowProperties.Title == w.Match
-	case WindowMatchClass:
-		return win.WindowProperties != nil && win.WindowProperties.Class == w.Match
-	case WindowMatchInstance:
-		return win.WindowProperties != nil && win.WindowProperties.Instance == w.Match
-	case WindowMatchWindowRole:
-		return win.WindowProperties != nil && win.WindowProperties.Role == w.Match
-	case WindowMatchWindowType:
-		return win.WindowProperties != nil && win.WindowProperties.Type == w.Match
+		if w.Regex {
+			return common.Errorf(string(w.Type), ErrRegexNotSupported)
+		}
+
+		if _, err := w.boolMatch(); err != nil {
+			return common.Errorf(string(w.Type)+" "+w.Match, ErrInvalidMatchValue)
+		}
 	default:
+		return common.Errorf(string(w.Type), ErrUnknownWindowMatchType)
+	}
+
+	if w.Regex {
+		if _, err := windowRegexp(w.Match); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MatchWindow checks if the window matches the identification. As the
+// workspace of the window is not known, "workspace" criteria never match,
+// use MatchWindowInWorkspace when the workspace is known.
+func (w WindowIdentification) MatchWindow(win *sway.Node) bool {
+	return w.MatchWindowInWorkspace(win, "")
+}
+
+func (w WindowIdentification) MatchWindowInWorkspace(win *sway.Node, workspace string) bool {
+	if win == nil {
 		return false
 	}
+
+	return w.match(win, workspace) != w.Not
 }
 
-func (w WindowIdentification) matchPartialWindow(win *sway.Node) bool {
-	switch w.Type {
-	case WindowMatchAppID:
-		return win.AppID != nil && strings.Contains(*win.AppID, w.Match)
-	case WindowMatchPID:
-		pid, err := strconv.ParseUint(w.Match, 10, 32)
-		if err != nil {
+func (w WindowIdentification) match(win *sway.Node, workspace string) bool {
+	if w.Type != "" && !w.matchProperty(win, workspace) {
+		return false
+	}
+
+	for _, sub := range w.All {
+		if !sub.MatchWindowInWorkspace(win, workspace) {
 			return false
 		}
+	}
 
-		return win.PID != nil && *win.PID == uint32(pid)
-	case WindowMatchShell:
-		return win.Shell != nil && *win.Shell == w.Match
-	case WindowMatchWindowID:
-		wid, err := strconv.ParseInt(w.Match, 10, 64)
-		if err != nil {
-			return false
+	if len(w.Any) == 0 {
+		return true
+	}
+
+	for _, sub := range w.Any {
+		if sub.MatchWindowInWorkspace(win, workspace) {
+			return true
 		}
+	}
 
-		return win.Window != nil && *win.Window == wid
+	return false
+}
+
+func (w WindowIdentification) matchProperty(win *sway.Node, workspace string) bool {
+	switch w.Type {
+	case WindowMatchAppID:
+		return win.AppID != nil && w.matchString(*win.AppID)
+	case WindowMatchPID:
+		return win.PID != nil && w.matchString(strconv.FormatUint(uint64(*win.PID), 10))
+	case WindowMatchShell:
+		return win.Shell != nil && w.matchString(*win.Shell)
+	case WindowMatchWindowID:
+		return win.Window != nil && w.matchString(strconv.FormatInt(*win.Window, 10))
 	case WindowMatchTitle:
-		return win.WindowProperties != nil && strings.Contains(win.WindowProperties.Title, w.Match)
+		return w.matchString(win.Name)
 	case WindowMatchClass:
-		return win.WindowProperties != nil && strings.Contains(win.WindowProperties.Class, w.Match)
+		return win.WindowProperties != nil && w.matchString(win.WindowProperties.Class)
 	case WindowMatchInstance:
-		return win.WindowProperties != nil && strings.Contains(win.WindowProperties.Instance, w.Match)
+		return win.WindowProperties != nil && w.matchString(win.WindowProperties.Instance)
 	case WindowMatchWindowRole:
-		return win.WindowProperties != nil && strings.Contains(win.WindowProperties.Role, w.Match)
+		return win.WindowProperties != nil && w.matchString(win.WindowProperties.Role)
 	case WindowMatchWindowType:
-		return win.WindowProperties != nil && strings.Contains(win.WindowProperties.Type, w.Match)
+		return win.WindowProperties != nil && w.matchString(win.WindowProperties.Type)
+	case WindowMatchMarks:
+		return slices.ContainsFunc(win.Marks, w.matchString)
+	case WindowMatchWorkspace:
+		return workspace != "" && w.matchString(workspace)
+	case WindowMatchFloating:
+		expected, err := w.boolMatch()
+		return err == nil && (win.Type == sway.NodeFloatingCon) == expected
+	case WindowMatchUrgent:
+		expected, err := w.boolMatch()
+		return err == nil && (win.Urgent != nil && *win.Urgent) == expected
 	default:
 		return false
 	}
 }
+
+func (w WindowIdentification) matchString(value string) bool {
+	switch {
+	case w.Regex:
+		re, err := windowRegexp(w.Match)
+		return err == nil && re.MatchString(value)
+	case w.Partial:
+		return strings.Contains(value, w.Match)
+	default:
+		return value == w.Match
+	}
+}
+
+// boolMatch reads the expected value for boolean properties, an empty match
+// means true.
+func (w WindowIdentification) boolMatch() (bool, error) {
+	if w.Match == "" {
+		return true, nil
+	}
+
+	return strconv.ParseBool(w.Match)
+}
+
+func windowRegexp(expr string) (*regexp.Regexp, error) {
+	windowRegexpsMu.Lock()
+	defer windowRegexpsMu.Unlock()
+
+	if re, ok := windowRegexps[expr]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	windowRegexps[expr] = re
+
+	return re, nil
+}
@@ -26,8 +26,21 @@ type Config struct {
 	Player    *Player    `yaml:"player"`
 	Volume    *Volume    `yaml:"volume"`
 	SwayNodes *SwayNodes `yaml:"sway"`
+	DND       *DND       `yaml:"dnd"`
 
-	CoreMessages NotificationSectionMessage `yaml:"core_messages"`
+	IdleInhibit *IdleInhibit `yaml:"idle_inhibit"`
+	Keyboard    *Keyboard    `yaml:"keyboard"`
+	Outputs     *Outputs     `yaml:"outputs"`
+	Urgent      *Urgent      `yaml:"urgent"`
+
+	FocusHistory *FocusHistory `yaml:"focus_history"`
+	Apps         *Apps         `yaml:"apps"`
+	Rules        *Rules        `yaml:"rules"`
+	Swallow      *Swallow      `yaml:"swallow"`
+	Bookmarks    *Bookmarks    `yaml:"bookmarks"`
+
+	CoreMessages  NotificationSectionMessage `yaml:"core_messages"`
+	Notifications *Notifications             `yaml:"notifications"`
 
 	notifier notifier          `yaml:"-"`
 	watcher  *fsnotify.Watcher `yaml:"-"`
@@ -41,10 +54,24 @@ var Default = &Config{
 	Player:    DefaultPlayer,
 	Volume:    DefaultVolume,
 	SwayNodes: DefaultSwayNodes,
+	DND:       DefaultDND,
+
+	IdleInhibit: DefaultIdleInhibit,
+	Keyboard:    DefaultKeyboard,
+	Outputs:     DefaultOutputs,
+	Urgent:      DefaultUrgent,
+
+	FocusHistory: DefaultFocusHistory,
+	Apps:         DefaultApps,
+	Rules:        DefaultRules,
+	Swallow:      DefaultSwallow,
+	Bookmarks:    DefaultBookmarks,
 	CoreMessages: NotificationSectionMessage{
-		Enabled: &trueValue,
-		Timeout: 3 * time.Second,
+		Enabled:       &trueValue,
+		Timeout:       3 * time.Second,
+		SuppressOnDND: &trueValue,
 	},
+	Notifications: DefaultNotifications,
 }
 
 func New(notif notifier) (*Config, error) {
@@ -114,6 +141,7 @@ func (c *Config) readPath(path string) bool {
 	}
 
 	c.applyDefault()
+	c.validate()
 
 	return true
 }
@@ -182,6 +210,17 @@ func (c *Config) announceReloaded() {
 	c.Player.announceReloaded(c.Player)
 	c.Volume.announceReloaded(c.Volume)
 	c.SwayNodes.announceReloaded(c.SwayNodes)
+	c.DND.announceReloaded(c.DND)
+	c.IdleInhibit.announceReloaded(c.IdleInhibit)
+	c.Keyboard.announceReloaded(c.Keyboard)
+	c.Outputs.announceReloaded(c.Outputs)
+	c.Urgent.announceReloaded(c.Urgent)
+	c.FocusHistory.announceReloaded(c.FocusHistory)
+	c.Apps.announceReloaded(c.Apps)
+	c.Rules.announceReloaded(c.Rules)
+	c.Swallow.announceReloaded(c.Swallow)
+	c.Bookmarks.announceReloaded(c.Bookmarks)
+	c.Notifications.announceReloaded(c.Notifications)
 
 	if c.notifier != nil {
 		c.notifier.Notify("Swaypanion configuration reloaded")
@@ -193,6 +232,17 @@ func (c *Config) applyDefault() {
 	c.Player.applyDefault()
 	c.Volume.applyDefault()
 	c.SwayNodes.applyDefault()
+	c.DND.applyDefault()
+	c.IdleInhibit.applyDefault()
+	c.Keyboard.applyDefault()
+	c.Outputs.applyDefault()
+	c.Urgent.applyDefault()
+	c.FocusHistory.applyDefault()
+	c.Apps.applyDefault()
+	c.Rules.applyDefault()
+	c.Swallow.applyDefault()
+	c.Bookmarks.applyDefault()
+	c.Notifications.applyDefault()
 
 	c.CoreMessages = c.CoreMessages.applyDefault(Default.CoreMessages)
 }
@@ -202,6 +252,17 @@ func (c *Config) reset() {
 	c.Player = &Player{}
 	c.Volume = &Volume{}
 	c.SwayNodes = &SwayNodes{}
+	c.DND = &DND{}
+	c.IdleInhibit = &IdleInhibit{}
+	c.Keyboard = &Keyboard{}
+	c.Outputs = &Outputs{}
+	c.Urgent = &Urgent{}
+	c.FocusHistory = &FocusHistory{}
+	c.Apps = &Apps{}
+	c.Rules = &Rules{}
+	c.Swallow = &Swallow{}
+	c.Bookmarks = &Bookmarks{}
+	c.Notifications = &Notifications{}
 
 	c.CoreMessages = NotificationSectionMessage{}
 }
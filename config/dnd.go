@@ -0,0 +1,15 @@
+package config
+
+type DND struct {
+	config[*DND] `yaml:"-"`
+
+	AutoFullscreen bool                   `yaml:"auto_fullscreen"`
+	AutoWindows    []WindowIdentification `yaml:"auto_windows"`
+}
+
+var DefaultDND = &DND{
+	AutoFullscreen: false,
+	AutoWindows:    []WindowIdentification{},
+}
+
+func (d *DND) applyDefault() {}
@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+type FocusHistory struct {
+	config[*FocusHistory] `yaml:"-"`
+
+	CycleTimeout time.Duration `yaml:"cycle_timeout"`
+}
+
+var DefaultFocusHistory = &FocusHistory{
+	CycleTimeout: time.Second,
+}
+
+func (f *FocusHistory) applyDefault() {
+	if f.CycleTimeout == 0 {
+		f.CycleTimeout = DefaultFocusHistory.CycleTimeout
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+	"time"
+
+	"github.com/willoma/swaypanion/common"
+)
+
+// IdleInhibitMethod defines how idleness is inhibited.
+type IdleInhibitMethod string
+
+const (
+	// Take an "idle" inhibitor lock from logind, which swayidle does not
+	// honour
+	IdleInhibitMethodLogind IdleInhibitMethod = "logind"
+	// Set "inhibit_idle open" on the sway windows without an inhibit_idle rule
+	IdleInhibitMethodSway IdleInhibitMethod = "sway"
+)
+
+var ErrUnknownIdleInhibitMethod = errors.New("unknown idle inhibit method")
+
+type IdleInhibit struct {
+	config[*IdleInhibit] `yaml:"-"`
+
+	Method     IdleInhibitMethod `yaml:"method"`
+	Fullscreen bool              `yaml:"fullscreen"`
+	// MediaPlayers lists the MPRIS players considered as video players:
+	// MPRIS does not tell whether the current track is a video, so any of
+	// these players inhibits idleness while it is playing
+	MediaPlayers []string               `yaml:"media_players"`
+	Windows      []WindowIdentification `yaml:"windows"`
+	PollInterval time.Duration          `yaml:"poll_interval"`
+}
+
+var DefaultIdleInhibit = &IdleInhibit{
+	Method:       IdleInhibitMethodSway,
+	Fullscreen:   false,
+	MediaPlayers: []string{},
+	Windows:      []WindowIdentification{},
+	PollInterval: time.Second,
+}
+
+func (i *IdleInhibit) applyDefault() {
+	switch i.Method {
+	case IdleInhibitMethodLogind, IdleInhibitMethodSway:
+	default:
+		if i.Method != "" {
+			common.LogError(
+				"Invalid configuration at idle_inhibit.method",
+				common.Errorf(string(i.Method), ErrUnknownIdleInhibitMethod),
+			)
+		}
+
+		i.Method = DefaultIdleInhibit.Method
+	}
+
+	if i.PollInterval == 0 {
+		i.PollInterval = DefaultIdleInhibit.PollInterval
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+type Keyboard struct {
+	config[*Keyboard] `yaml:"-"`
+
+	// Identifier of the keyboard to report and switch, all keyboards are
+	// switched and the first one is reported if empty
+	Identifier string `yaml:"identifier"`
+	PerWindow  bool   `yaml:"per_window"`
+}
+
+var DefaultKeyboard = &Keyboard{
+	Identifier: "",
+	PerWindow:  false,
+}
+
+func (k *Keyboard) applyDefault() {}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/willoma/swaypanion/common"
+)
+
+type NotificationBackend string
+
+const (
+	NotificationBackendDBus   NotificationBackend = "dbus"
+	NotificationBackendLog    NotificationBackend = "log"
+	NotificationBackendSocket NotificationBackend = "socket"
+)
+
+var ErrInvalidHistorySize = errors.New("history size must be positive")
+
+type Notifications struct {
+	config[*Notifications] `yaml:"-"`
+
+	Backend        NotificationBackend `yaml:"backend"`
+	HistoryEnabled *bool               `yaml:"history_enabled"`
+	HistorySize    int                 `yaml:"history_size"`
+}
+
+var DefaultNotifications = &Notifications{
+	Backend:        NotificationBackendDBus,
+	HistoryEnabled: &trueValue,
+	HistorySize:    500,
+}
+
+func (n *Notifications) applyDefault() {
+	if n.Backend == "" {
+		n.Backend = DefaultNotifications.Backend
+	}
+
+	if n.HistoryEnabled == nil {
+		n.HistoryEnabled = DefaultNotifications.HistoryEnabled
+	}
+
+	if n.HistorySize <= 0 {
+		if n.HistorySize < 0 {
+			common.LogError(
+				"Invalid configuration at notifications.history_size",
+				common.Errorf(strconv.Itoa(n.HistorySize), ErrInvalidHistorySize),
+			)
+		}
+
+		n.HistorySize = DefaultNotifications.HistorySize
+	}
+}
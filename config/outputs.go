@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"time"
+
+	"github.com/willoma/swaypanion/common"
+)
+
+var (
+	ErrMissingProfileName = errors.New("missing profile name")
+	ErrMissingOutputMatch = errors.New("missing output match")
+)
+
+// OutputSettings are the settings applied to an output when its profile is
+// applied. Empty settings are left untouched.
+type OutputSettings struct {
+	// Output name (eg. "eDP-1"), or make, model and serial separated by
+	// spaces (eg. "Dell Inc. DELL U2720Q ABC123"), or "*"
+	Match     string  `yaml:"match"`
+	Enabled   *bool   `yaml:"enabled"`
+	Mode      string  `yaml:"mode"`
+	Position  string  `yaml:"position"`
+	Scale     float64 `yaml:"scale"`
+	Transform string  `yaml:"transform"`
+}
+
+// OutputProfile is applied when the connected outputs are exactly the
+// outputs of the profile.
+type OutputProfile struct {
+	Name    string           `yaml:"name"`
+	Outputs []OutputSettings `yaml:"outputs"`
+}
+
+type Outputs struct {
+	config[*Outputs] `yaml:"-"`
+
+	AutoApply    *bool                      `yaml:"auto_apply"`
+	Profiles     []OutputProfile            `yaml:"profiles"`
+	Notification NotificationSectionMessage `yaml:"notification"`
+}
+
+var DefaultOutputs = &Outputs{
+	AutoApply: &trueValue,
+	Profiles:  []OutputProfile{},
+	Notification: NotificationSectionMessage{
+		Enabled:       &trueValue,
+		Timeout:       3 * time.Second,
+		SuppressOnDND: &trueValue,
+	},
+}
+
+func (o *Outputs) applyDefault() {
+	if o.AutoApply == nil {
+		o.AutoApply = DefaultOutputs.AutoApply
+	}
+
+	profiles := make([]OutputProfile, 0, len(o.Profiles))
+
+	for _, profile := range o.Profiles {
+		if profile.Name == "" {
+			common.LogError("Invalid configuration at outputs.profiles", ErrMissingProfileName)
+			continue
+		}
+
+		valid := true
+
+		for _, output := range profile.Outputs {
+			if output.Match == "" {
+				common.LogError(
+					"Invalid configuration at outputs.profiles",
+					common.Errorf(profile.Name, ErrMissingOutputMatch),
+				)
+
+				valid = false
+			}
+		}
+
+		if valid {
+			profiles = append(profiles, profile)
+		}
+	}
+
+	o.Profiles = profiles
+
+	o.Notification = o.Notification.applyDefault(DefaultOutputs.Notification)
+}
@@ -3,21 +3,15 @@ package config
 type Player struct {
 	config[*Player] `yaml:"-"`
 
-	PlayerName string  `yaml:"player_name"`
-	Start      Command `yaml:"start"`
-	Show       Command `yaml:"show"`
+	PlayerName string `yaml:"player_name"`
+	// App is the entry of the apps section used to show or start the
+	// player window
+	App string `yaml:"app"`
 }
 
 var DefaultPlayer = &Player{
 	PlayerName: "spotify",
-	Start: Command{
-		Type:    CommandTypeShell,
-		Command: "/usr/bin/spotify",
-	},
-	Show: Command{
-		Type:    CommandTypeSway,
-		Command: "[instance=spotify] move workspace current, focus",
-	},
+	App:        "spotify",
 }
 
 func (p *Player) applyDefault() {
@@ -25,19 +19,7 @@ func (p *Player) applyDefault() {
 		p.PlayerName = DefaultPlayer.PlayerName
 	}
 
-	if p.Start.Type == "" {
-		p.Start.Type = DefaultPlayer.Start.Type
-	}
-
-	if p.Start.Command == "" {
-		p.Start.Command = DefaultPlayer.Start.Command
-	}
-
-	if p.Show.Type == "" {
-		p.Show.Type = DefaultPlayer.Show.Type
-	}
-
-	if p.Show.Command == "" {
-		p.Show.Command = DefaultPlayer.Show.Command
+	if p.App == "" {
+		p.App = DefaultPlayer.App
 	}
 }
@@ -0,0 +1,47 @@
+package config
+
+// RuleAction is applied to a window matched by a rule. All fields are
+// optional, sway commands are applied in the following order: floating,
+// workspace, resize, mark, hide, sway. The run command is executed last.
+type RuleAction struct {
+	Floating  *bool   `yaml:"floating"`
+	Workspace string  `yaml:"workspace"`
+	Resize    string  `yaml:"resize"`
+	Mark      string  `yaml:"mark"`
+	Hide      bool    `yaml:"hide"`
+	Sway      string  `yaml:"sway"`
+	Run       Command `yaml:"run"`
+}
+
+// Rule is applied when a window starts matching it, either when it appears
+// or when its title changes.
+type Rule struct {
+	Window  WindowIdentification `yaml:"window"`
+	Actions []RuleAction         `yaml:"actions"`
+}
+
+type Rules struct {
+	config[*Rules] `yaml:"-"`
+
+	Rules map[string]Rule `yaml:",inline"`
+}
+
+var DefaultRules = &Rules{
+	Rules: map[string]Rule{},
+}
+
+func (r *Rules) applyDefault() {
+	if r.Rules == nil {
+		r.Rules = map[string]Rule{}
+	}
+
+	for name, rule := range r.Rules {
+		for i, action := range rule.Actions {
+			if action.Run.Command != "" && action.Run.Type == "" {
+				rule.Actions[i].Run.Type = CommandTypeShell
+			}
+		}
+
+		r.Rules[name] = rule
+	}
+}
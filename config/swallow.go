@@ -0,0 +1,26 @@
+package config
+
+type Swallow struct {
+	config[*Swallow] `yaml:"-"`
+
+	Enabled   bool                   `yaml:"enabled"`
+	Terminals []WindowIdentification `yaml:"terminals"`
+	Exclude   []WindowIdentification `yaml:"exclude"`
+}
+
+var DefaultSwallow = &Swallow{
+	Enabled: false,
+	Terminals: []WindowIdentification{
+		{Type: WindowMatchAppID, Match: "foot"},
+		{Type: WindowMatchAppID, Match: "Alacritty"},
+		{Type: WindowMatchAppID, Match: "kitty"},
+	},
+	Exclude: []WindowIdentification{},
+}
+
+func (s *Swallow) applyDefault() {
+	if len(s.Terminals) == 0 {
+		s.Terminals = make([]WindowIdentification, len(DefaultSwallow.Terminals))
+		copy(s.Terminals, DefaultSwallow.Terminals)
+	}
+}
@@ -1,14 +1,78 @@
 package config
 
+import (
+	"errors"
+
+	"github.com/willoma/swaypanion/common"
+)
+
+// OutputNumbering defines how workspaces are numbered when there are several
+// outputs.
+type OutputNumbering string
+
+const (
+	// Workspaces are numbered from 1 on each output
+	OutputNumberingPerOutput OutputNumbering = "per_output"
+	// Workspaces are numbered from 1 on the first output, numbering
+	// continues on the next outputs
+	OutputNumberingGlobal OutputNumbering = "global"
+	// Each output has its own range of numbers: 1 to 10, 11 to 20, etc
+	OutputNumberingRanges OutputNumbering = "ranges"
+)
+
+var ErrUnknownOutputNumbering = errors.New("unknown output numbering")
+
+type AppIcon struct {
+	Window WindowIdentification `yaml:",inline"`
+	Icon   string               `yaml:"icon"`
+}
+
 type SwayNodes struct {
 	config[*SwayNodes] `yaml:"-"`
 
 	EmptyWorkspaceName string                 `yaml:"empty_workspace_name"`
+	AutoCompact        bool                   `yaml:"auto_compact"`
+	OutputNumbering    OutputNumbering        `yaml:"output_numbering"`
+	OutputRangeSize    int                    `yaml:"output_range_size"`
+	AutoName           bool                   `yaml:"auto_name"`
+	Autotile           bool                   `yaml:"autotile"`
+	AutotileWorkspaces []string               `yaml:"autotile_workspaces"`
+	AppIcons           []AppIcon              `yaml:"app_icons"`
+	FallbackIcon       string                 `yaml:"fallback_icon"`
+	DedupIcons         *bool                  `yaml:"dedup_icons"`
+	IconSeparator      string                 `yaml:"icon_separator"`
 	HideInsteadOfClose []WindowIdentification `yaml:"hide_instead_of_close"`
 }
 
 var DefaultSwayNodes = &SwayNodes{
 	EmptyWorkspaceName: " ",
+	AutoCompact:        false,
+	OutputNumbering:    OutputNumberingPerOutput,
+	OutputRangeSize:    10,
+	AutoName:           false,
+	Autotile:           false,
+	AutotileWorkspaces: []string{},
+	AppIcons: []AppIcon{
+		{
+			Window: WindowIdentification{Type: WindowMatchAppID, Match: "firefox"},
+			Icon:   "",
+		},
+		{
+			Window: WindowIdentification{Type: WindowMatchAppID, Match: "Alacritty"},
+			Icon:   "",
+		},
+		{
+			Window: WindowIdentification{Type: WindowMatchAppID, Match: "foot"},
+			Icon:   "",
+		},
+		{
+			Window: WindowIdentification{Type: WindowMatchInstance, Match: "spotify"},
+			Icon:   "",
+		},
+	},
+	FallbackIcon:  "",
+	DedupIcons:    &trueValue,
+	IconSeparator: " ",
 	HideInsteadOfClose: []WindowIdentification{
 		{
 			Type:    WindowMatchInstance,
@@ -23,6 +87,40 @@ func (s *SwayNodes) applyDefault() {
 		s.EmptyWorkspaceName = DefaultSwayNodes.EmptyWorkspaceName
 	}
 
+	switch s.OutputNumbering {
+	case OutputNumberingPerOutput, OutputNumberingGlobal, OutputNumberingRanges:
+	default:
+		if s.OutputNumbering != "" {
+			common.LogError(
+				"Invalid configuration at sway.output_numbering",
+				common.Errorf(string(s.OutputNumbering), ErrUnknownOutputNumbering),
+			)
+		}
+
+		s.OutputNumbering = DefaultSwayNodes.OutputNumbering
+	}
+
+	if s.OutputRangeSize <= 0 {
+		s.OutputRangeSize = DefaultSwayNodes.OutputRangeSize
+	}
+
+	if len(s.AppIcons) == 0 {
+		s.AppIcons = make([]AppIcon, len(DefaultSwayNodes.AppIcons))
+		copy(s.AppIcons, DefaultSwayNodes.AppIcons)
+	}
+
+	if s.FallbackIcon == "" {
+		s.FallbackIcon = DefaultSwayNodes.FallbackIcon
+	}
+
+	if s.DedupIcons == nil {
+		s.DedupIcons = DefaultSwayNodes.DedupIcons
+	}
+
+	if s.IconSeparator == "" {
+		s.IconSeparator = DefaultSwayNodes.IconSeparator
+	}
+
 	if len(s.HideInsteadOfClose) == 0 {
 		s.HideInsteadOfClose = make([]WindowIdentification, len(DefaultSwayNodes.HideInsteadOfClose))
 		copy(s.HideInsteadOfClose, DefaultSwayNodes.HideInsteadOfClose)
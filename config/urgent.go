@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+type Urgent struct {
+	config[*Urgent] `yaml:"-"`
+
+	// Notification is sent when a window becomes urgent while not visible
+	Notification NotificationSectionMessage `yaml:"notification"`
+}
+
+var DefaultUrgent = &Urgent{
+	Notification: NotificationSectionMessage{
+		Enabled:       &falseValue,
+		Timeout:       5 * time.Second,
+		SuppressOnDND: &trueValue,
+	},
+}
+
+func (u *Urgent) applyDefault() {
+	u.Notification = u.Notification.applyDefault(DefaultUrgent.Notification)
+}
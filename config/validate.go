@@ -0,0 +1,73 @@
+package config
+
+import (
+	"strconv"
+
+	"github.com/willoma/swaypanion/common"
+)
+
+// validate drops the configuration entries whose errors can only be detected
+// once the configuration has been read, reporting them.
+func (c *Config) validate() {
+	c.SwayNodes.AppIcons = validWindowIdentifications(
+		"sway.app_icons", c.SwayNodes.AppIcons, func(a AppIcon) WindowIdentification { return a.Window },
+	)
+	c.SwayNodes.HideInsteadOfClose = validWindowIdentifications(
+		"sway.hide_instead_of_close", c.SwayNodes.HideInsteadOfClose, windowIdentification,
+	)
+	c.DND.AutoWindows = validWindowIdentifications(
+		"dnd.auto_windows", c.DND.AutoWindows, windowIdentification,
+	)
+	c.IdleInhibit.Windows = validWindowIdentifications(
+		"idle_inhibit.windows", c.IdleInhibit.Windows, windowIdentification,
+	)
+	c.Swallow.Terminals = validWindowIdentifications(
+		"swallow.terminals", c.Swallow.Terminals, windowIdentification,
+	)
+	c.Swallow.Exclude = validWindowIdentifications(
+		"swallow.exclude", c.Swallow.Exclude, windowIdentification,
+	)
+
+	for name, app := range c.Apps.Apps {
+		if !validWindowIdentification("apps."+name+".window", app.Window) {
+			delete(c.Apps.Apps, name)
+		}
+	}
+
+	for name, rule := range c.Rules.Rules {
+		if !validWindowIdentification("rules."+name+".window", rule.Window) {
+			delete(c.Rules.Rules, name)
+		}
+	}
+}
+
+func windowIdentification(id WindowIdentification) WindowIdentification {
+	return id
+}
+
+// validWindowIdentifications returns the elements of list whose window
+// identification is valid.
+func validWindowIdentifications[T any](path string, list []T, id func(T) WindowIdentification) []T {
+	if len(list) == 0 {
+		return list
+	}
+
+	valid := make([]T, 0, len(list))
+
+	for i, elem := range list {
+		if validWindowIdentification(path+"["+strconv.Itoa(i)+"]", id(elem)) {
+			valid = append(valid, elem)
+		}
+	}
+
+	return valid
+}
+
+func validWindowIdentification(path string, id WindowIdentification) bool {
+	if err := id.Validate(); err != nil {
+		common.LogError("Invalid window identification in configuration at "+path, err)
+		return false
+	}
+
+	return true
+}
@@ -24,6 +24,8 @@ var DefaultVolume = &Volume{
 			" {value} %",
 			" {value} %",
 		},
+		StackTag:      "swaypanion-volume",
+		SuppressOnDND: &trueValue,
 	},
 }
 
@@ -0,0 +1,283 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+)
+
+const appLaunchTimeout = 30 * time.Second
+
+var (
+	errUnknownApp       = errors.New("unknown app")
+	errAppWindowTimeout = errors.New("app window did not appear")
+)
+
+type Apps struct {
+	sway *sway.Client
+	stop func()
+
+	mu        sync.Mutex
+	apps      map[string]config.App
+	launching map[string]bool
+}
+
+func NewApps(conf *config.Apps, swayClient *sway.Client) *Apps {
+	a := &Apps{
+		sway:      swayClient,
+		launching: map[string]bool{},
+	}
+
+	a.reloadConfig(conf)
+	a.stop = conf.ListenReload(a.reloadConfig)
+
+	return a
+}
+
+func (a *Apps) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stop != nil {
+		a.stop()
+		a.stop = nil
+	}
+}
+
+func (a *Apps) reloadConfig(conf *config.Apps) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.apps = make(map[string]config.App, len(conf.Apps))
+	for name, app := range conf.Apps {
+		a.apps[name] = app
+	}
+}
+
+func (a *Apps) app(name string) (config.App, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	app, ok := a.apps[name]
+	if !ok {
+		return config.App{}, common.Errorf(name, errUnknownApp)
+	}
+
+	return app, nil
+}
+
+// matching returns the app the window, in workspace, belongs to.
+func (a *Apps) matching(window *sway.Node, workspace string) (string, config.App, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for name, app := range a.apps {
+		if app.Window.MatchWindowInWorkspace(window, workspace) {
+			return name, app, true
+		}
+	}
+
+	return "", config.App{}, false
+}
+
+// identify returns the configured app matching the window in workspace, or the
+// properties identifying the window if no app matches.
+func (a *Apps) identify(window *sway.Node, workspace string) (string, *config.WindowIdentification) {
+	if name, app, ok := a.matching(window, workspace); ok {
+		return name, &app.Window
+	}
+
+	id := &config.WindowIdentification{Type: config.WindowMatchAppID}
+
+	switch {
+	case window.AppID != nil && *window.AppID != "":
+		id.Match = *window.AppID
+	case window.WindowProperties != nil:
+		id.Type = config.WindowMatchClass
+		id.Match = window.WindowProperties.Class
+	}
+
+	return "", id
+}
+
+// focus shows the window of the app, launching the app if needed.
+func (a *Apps) focus(name string) error {
+	app, err := a.app(name)
+	if err != nil {
+		return err
+	}
+
+	hidden, err := a.sway.HiddenWindows()
+	if err != nil {
+		return err
+	}
+
+	for _, window := range hidden {
+		if app.Window.MatchWindowInWorkspace(window, sway.ScratchpadName) {
+			return a.sway.RunCommand(fmt.Sprintf("[con_id=%d] scratchpad show", window.ID))
+		}
+	}
+
+	windows, workspaces, err := a.sway.WindowsWithWorkspaces()
+	if err != nil {
+		return err
+	}
+
+	for _, window := range windows {
+		if app.Window.MatchWindowInWorkspace(window, workspaces[window.ID]) {
+			return a.sway.RunCommand(fmt.Sprintf("[con_id=%d] focus", window.ID))
+		}
+	}
+
+	return a.launch(name, app)
+}
+
+// toggle hides the window of the app if it is focused, or shows it otherwise.
+func (a *Apps) toggle(name string) error {
+	app, err := a.app(name)
+	if err != nil {
+		return err
+	}
+
+	focused, err := a.sway.FocusedNode()
+	if err != nil && !errors.Is(err, sway.ErrCurrentWindowNotFound) {
+		return err
+	}
+
+	workspaces, err := a.sway.WindowsWorkspaces()
+	if err != nil {
+		return err
+	}
+
+	if focused != nil && sway.IsWindow(focused) && app.Window.MatchWindowInWorkspace(focused, workspaces[focused.ID]) {
+		return a.sway.RunCommand(fmt.Sprintf("[con_id=%d] move to scratchpad", focused.ID))
+	}
+
+	return a.focus(name)
+}
+
+// launch starts the app, then waits for its window to appear in order to
+// place it.
+func (a *Apps) launch(name string, app config.App) error {
+	a.mu.Lock()
+	if a.launching[name] {
+		a.mu.Unlock()
+		return nil
+	}
+	a.launching[name] = true
+	a.mu.Unlock()
+
+	appeared, err := a.start(app)
+	if err != nil {
+		a.endLaunch(name)
+		return err
+	}
+
+	go func() {
+		defer a.endLaunch(name)
+
+		id, err := appeared()
+		if err != nil {
+			common.LogError("Failed to launch app "+name, err)
+			return
+		}
+
+		if err := a.place(id, app); err != nil {
+			common.LogError("Failed to place window of app "+name, err)
+		}
+	}()
+
+	return nil
+}
+
+func (a *Apps) endLaunch(name string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.launching, name)
+}
+
+// start runs the launch command of the app. The returned function waits for
+// the window of the app to appear and returns its ID.
+func (a *Apps) start(app config.App) (func() (int64, error), error) {
+	appeared := make(chan int64, 1)
+
+	// Some applications only set their properties after the window is
+	// created, title changes are considered as well, but only for windows
+	// created after the subscription: an already open instance of the app
+	// must not be taken for the launched one. Events are received by a
+	// single goroutine, created needs no lock.
+	created := map[int64]struct{}{}
+
+	a.sway.WindowEvents().Subscribe(appeared, func(event sway.WindowEvent) {
+		switch event.Change {
+		case sway.WindowNew:
+			created[event.Container.ID] = struct{}{}
+		case sway.WindowTitle:
+			if _, ok := created[event.Container.ID]; !ok {
+				return
+			}
+		default:
+			return
+		}
+
+		workspaces, err := a.sway.WindowsWorkspaces()
+		if err != nil {
+			common.LogError("Failed to get workspaces", err)
+			return
+		}
+
+		if app.Window.MatchWindowInWorkspace(&event.Container, workspaces[event.Container.ID]) {
+			select {
+			case appeared <- event.Container.ID:
+			default:
+			}
+		}
+	})
+
+	if err := app.Launch.Run(a.sway); err != nil {
+		a.sway.WindowEvents().Unsubscribe(appeared)
+		return nil, err
+	}
+
+	return func() (int64, error) {
+		defer a.sway.WindowEvents().Unsubscribe(appeared)
+
+		select {
+		case id := <-appeared:
+			return id, nil
+		case <-time.After(appLaunchTimeout):
+			return 0, errAppWindowTimeout
+		}
+	}, nil
+}
+
+func (a *Apps) place(id int64, app config.App) error {
+	if app.Scratchpad {
+		if err := a.sway.RunCommand(fmt.Sprintf("[con_id=%d] move to scratchpad", id)); err != nil {
+			return err
+		}
+
+		return a.sway.RunCommand(fmt.Sprintf("[con_id=%d] scratchpad show", id))
+	}
+
+	commands := []string{}
+
+	if app.Floating {
+		commands = append(commands, "floating enable")
+	}
+
+	if app.Workspace != "" {
+		commands = append(commands, fmt.Sprintf(`move to workspace "%s"`, strings.ReplaceAll(app.Workspace, `"`, `\"`)))
+	}
+
+	commands = append(commands, "focus")
+
+	return a.sway.RunCommand(fmt.Sprintf("[con_id=%d] %s", id, strings.Join(commands, ", ")))
+}
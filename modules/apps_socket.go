@@ -0,0 +1,47 @@
+package modules
+
+import (
+	"errors"
+
+	"github.com/willoma/swaypanion/common"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+)
+
+const appLabel = "app"
+
+func (a *Apps) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		a.socketFocus, appLabel+" focus", "Focus an app, launching it if needed", "app name",
+		a.socketToggle, appLabel+" toggle", "Focus an app, or hide it if it is focused", "app name",
+	)
+}
+
+func (a *Apps) socketFocus(conn *socketserver.Connection, value string, _ []string) {
+	if value == "" {
+		conn.SendError("missing app name")
+		return
+	}
+
+	if err := a.focus(value); err != nil {
+		if !errors.Is(err, errUnknownApp) {
+			common.LogError("Failed to focus app", err)
+		}
+
+		conn.SendError("failed to focus app: " + err.Error())
+	}
+}
+
+func (a *Apps) socketToggle(conn *socketserver.Connection, value string, _ []string) {
+	if value == "" {
+		conn.SendError("missing app name")
+		return
+	}
+
+	if err := a.toggle(value); err != nil {
+		if !errors.Is(err, errUnknownApp) {
+			common.LogError("Failed to toggle app", err)
+		}
+
+		conn.SendError("failed to toggle app: " + err.Error())
+	}
+}
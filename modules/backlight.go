@@ -51,6 +51,7 @@ func (b *Backlight) Stop() {
 	}
 
 	b.subscriptions.Unsubscribe(b.notifier)
+	b.notifier.Close()
 }
 
 func (b *Backlight) reloadConfig(conf *config.Backlight) {
@@ -0,0 +1,307 @@
+package modules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+)
+
+const (
+	bookmarksFileName  = "bookmarks.json"
+	bookmarkMarkPrefix = "_swaypanion_bookmark_"
+)
+
+var (
+	errInvalidBookmarkKey = errors.New("invalid bookmark key")
+	errUnknownBookmark    = errors.New("unknown bookmark")
+	errBookmarkClosed     = errors.New("bookmarked window is closed")
+	errNoBookmarkLaunch   = errors.New("no way to launch bookmarked window")
+)
+
+// bookmark is persisted, so that the window can be launched again after it
+// has been closed.
+type bookmark struct {
+	App    string                       `json:"app,omitempty"`
+	Window *config.WindowIdentification `json:"window,omitempty"`
+	Title  string                       `json:"title,omitempty"`
+}
+
+type bookmarkEntry struct {
+	key    string
+	app    string
+	title  string
+	window int64
+}
+
+// Bookmarks marks windows under a key, in order to go back to them later.
+type Bookmarks struct {
+	sway *sway.Client
+	apps *Apps
+	stop func()
+
+	mu        sync.Mutex
+	path      string
+	launch    map[string]config.Command
+	bookmarks map[string]bookmark
+}
+
+func NewBookmarks(conf *config.Bookmarks, swayClient *sway.Client, apps *Apps) *Bookmarks {
+	b := &Bookmarks{
+		sway:      swayClient,
+		apps:      apps,
+		bookmarks: map[string]bookmark{},
+	}
+
+	if dir, err := common.StateDir(); err != nil {
+		common.LogError("Failed to find bookmarks directory", err)
+	} else {
+		b.path = filepath.Join(dir, bookmarksFileName)
+		b.load()
+	}
+
+	b.reloadConfig(conf)
+	b.stop = conf.ListenReload(b.reloadConfig)
+
+	return b
+}
+
+func (b *Bookmarks) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stop != nil {
+		b.stop()
+		b.stop = nil
+	}
+}
+
+func (b *Bookmarks) reloadConfig(conf *config.Bookmarks) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.launch = make(map[string]config.Command, len(conf.Launch))
+	for key, command := range conf.Launch {
+		b.launch[key] = command
+	}
+}
+
+func (b *Bookmarks) load() {
+	content, err := os.ReadFile(b.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			common.LogError("Failed to read bookmarks", err)
+		}
+
+		return
+	}
+
+	if err := json.Unmarshal(content, &b.bookmarks); err != nil {
+		common.LogError("Failed to read bookmarks", err)
+	}
+}
+
+func (b *Bookmarks) unsafeSave() error {
+	if b.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(b.bookmarks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(b.path, content, 0o600)
+}
+
+func bookmarkMark(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, `"\`) {
+		return "", common.Errorf(key, errInvalidBookmarkKey)
+	}
+
+	return bookmarkMarkPrefix + key, nil
+}
+
+// findMarked returns the window with the mark, or nil if it does not exist.
+func (b *Bookmarks) findMarked(mark string) (*sway.Node, string, error) {
+	windows, workspaces, err := b.sway.WindowsWithWorkspaces()
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, window := range windows {
+		if slices.Contains(window.Marks, mark) {
+			return window, workspaces[window.ID], nil
+		}
+	}
+
+	return nil, "", nil
+}
+
+// set bookmarks the focused window. As sway marks are unique, a window
+// previously bookmarked under the same key loses the bookmark.
+func (b *Bookmarks) set(key string) error {
+	mark, err := bookmarkMark(key)
+	if err != nil {
+		return err
+	}
+
+	window, _, workspace, err := b.sway.FocusedWindow()
+	if err != nil {
+		return err
+	}
+
+	if err := b.sway.RunCommand(fmt.Sprintf(`[con_id=%d] mark --add "%s"`, window.ID, mark)); err != nil {
+		return err
+	}
+
+	app, id := b.apps.identify(window, workspace.Name)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bookmarks[key] = bookmark{
+		App:    app,
+		Window: id,
+		Title:  window.Name,
+	}
+
+	return b.unsafeSave()
+}
+
+// goTo focuses the bookmarked window, launching it again if it has been
+// closed.
+func (b *Bookmarks) goTo(key string) error {
+	mark, err := bookmarkMark(key)
+	if err != nil {
+		return err
+	}
+
+	window, workspace, err := b.findMarked(mark)
+	if err != nil {
+		return err
+	}
+
+	if window != nil {
+		if workspace == sway.ScratchpadName {
+			return b.sway.RunCommand(fmt.Sprintf("[con_id=%d] scratchpad show", window.ID))
+		}
+
+		return b.sway.RunCommand(fmt.Sprintf("[con_id=%d] focus", window.ID))
+	}
+
+	id, err := b.relaunch(key)
+	if err != nil {
+		return err
+	}
+
+	return b.sway.RunCommand(fmt.Sprintf(`[con_id=%d] mark --add "%s", focus`, id, mark))
+}
+
+// relaunch runs the launch command configured for the key, or launches the
+// app the window belonged to, then waits for the window to appear.
+func (b *Bookmarks) relaunch(key string) (int64, error) {
+	b.mu.Lock()
+	saved, ok := b.bookmarks[key]
+	command, hasCommand := b.launch[key]
+	b.mu.Unlock()
+
+	if !ok {
+		return 0, common.Errorf(key, errUnknownBookmark)
+	}
+
+	var (
+		app config.App
+		err error
+	)
+
+	switch {
+	case hasCommand && saved.Window != nil:
+		app = config.App{Window: *saved.Window, Launch: command}
+	case saved.App != "":
+		app, err = b.apps.app(saved.App)
+		if err != nil {
+			return 0, err
+		}
+	default:
+		return 0, common.Errorf(key, errNoBookmarkLaunch)
+	}
+
+	appeared, err := b.apps.start(app)
+	if err != nil {
+		return 0, err
+	}
+
+	return appeared()
+}
+
+// swap swaps the focused window with the bookmarked window.
+func (b *Bookmarks) swap(key string) error {
+	mark, err := bookmarkMark(key)
+	if err != nil {
+		return err
+	}
+
+	window, _, err := b.findMarked(mark)
+	if err != nil {
+		return err
+	}
+
+	if window == nil {
+		return common.Errorf(key, errBookmarkClosed)
+	}
+
+	return b.sway.RunCommand(fmt.Sprintf(`swap container with mark "%s"`, mark))
+}
+
+// list returns the bookmarks, sorted by key. The app and title of open
+// windows are the current ones.
+func (b *Bookmarks) list() ([]bookmarkEntry, error) {
+	windows, err := b.sway.Windows()
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entries := make([]bookmarkEntry, 0, len(b.bookmarks))
+
+	for key, saved := range b.bookmarks {
+		entry := bookmarkEntry{
+			key:   key,
+			app:   saved.App,
+			title: saved.Title,
+		}
+
+		if entry.app == "" && saved.Window != nil {
+			entry.app = saved.Window.Match
+		}
+
+		for _, window := range windows {
+			if slices.Contains(window.Marks, bookmarkMarkPrefix+key) {
+				entry.app = sway.AppName(window)
+				entry.title = window.Name
+				entry.window = window.ID
+
+				break
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	slices.SortFunc(entries, func(a, b bookmarkEntry) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	return entries, nil
+}
@@ -0,0 +1,83 @@
+package modules
+
+import (
+	"strconv"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+)
+
+func (e bookmarkEntry) message() socket.Message {
+	msg := socket.Message{
+		Command: windowLabel + " mark",
+		Value:   e.key,
+		Complement: []string{
+			"App: " + e.app,
+			"Title: " + e.title,
+		},
+	}
+
+	if e.window != 0 {
+		msg.Complement = append(msg.Complement, "Window: "+strconv.FormatInt(e.window, 10))
+	} else {
+		msg.Complement = append(msg.Complement, "Window: closed")
+	}
+
+	return msg
+}
+
+func (b *Bookmarks) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		b.socketSet, windowLabel+" mark set", "Bookmark the focused window", "key",
+		b.socketGoto, windowLabel+" mark goto", "Focus the bookmarked window, launching it again if it has been closed", "key",
+		b.socketSwap, windowLabel+" mark swap", "Swap the focused window with the bookmarked window", "key",
+		b.socketList, windowLabel+" mark list", "List bookmarked windows",
+	)
+}
+
+func (b *Bookmarks) socketSet(conn *socketserver.Connection, value string, _ []string) {
+	if err := b.set(value); err != nil {
+		common.LogError("Failed to bookmark window", err)
+		conn.SendError("failed to bookmark window: " + err.Error())
+	}
+}
+
+func (b *Bookmarks) socketGoto(conn *socketserver.Connection, value string, _ []string) {
+	if err := b.goTo(value); err != nil {
+		common.LogError("Failed to go to bookmarked window", err)
+		conn.SendError("failed to go to bookmarked window: " + err.Error())
+	}
+}
+
+func (b *Bookmarks) socketSwap(conn *socketserver.Connection, value string, _ []string) {
+	if err := b.swap(value); err != nil {
+		common.LogError("Failed to swap with bookmarked window", err)
+		conn.SendError("failed to swap with bookmarked window: " + err.Error())
+	}
+}
+
+func (b *Bookmarks) socketList(conn *socketserver.Connection, _ string, _ []string) {
+	entries, err := b.list()
+	if err != nil {
+		common.LogError("Failed to list bookmarks", err)
+		conn.SendError("failed to list bookmarks")
+
+		return
+	}
+
+	if len(entries) == 0 {
+		if err := conn.SendString(windowLabel+" mark", "no bookmark"); err != nil {
+			common.LogError("Failed to send bookmarks", err)
+		}
+
+		return
+	}
+
+	for _, entry := range entries {
+		if err := conn.Send(entry.message()); err != nil {
+			common.LogError("Failed to send bookmarks", err)
+			return
+		}
+	}
+}
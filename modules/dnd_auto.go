@@ -0,0 +1,140 @@
+package modules
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/notification"
+	"github.com/willoma/swaypanion/sway"
+)
+
+// DNDAuto enables the do-not-disturb mode while a window is fullscreen or
+// while a configured window exists. Windows are checked again on each window
+// event which may change the result.
+type DNDAuto struct {
+	sway *sway.Client
+	dnd  *notification.DND
+
+	stop func()
+
+	mu         sync.Mutex
+	subscribed bool
+	fullscreen bool
+	windows    []config.WindowIdentification
+}
+
+func NewDNDAuto(conf *config.DND, swayClient *sway.Client, notif *notification.Notification) *DNDAuto {
+	d := &DNDAuto{
+		sway: swayClient,
+		dnd:  notif.DND(),
+	}
+
+	d.reloadConfig(conf)
+	d.stop = conf.ListenReload(d.reloadConfig)
+
+	d.sway.OnConnected(d.evaluate)
+
+	return d
+}
+
+func (d *DNDAuto) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop != nil {
+		d.stop()
+		d.stop = nil
+	}
+
+	if d.subscribed {
+		d.sway.WindowEvents().Unsubscribe(d)
+		d.subscribed = false
+	}
+}
+
+func (d *DNDAuto) reloadConfig(conf *config.DND) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.fullscreen = conf.AutoFullscreen
+	d.windows = make([]config.WindowIdentification, len(conf.AutoWindows))
+	copy(d.windows, conf.AutoWindows)
+
+	enabled := d.fullscreen || len(d.windows) > 0
+
+	switch {
+	case enabled && !d.subscribed:
+		d.sway.WindowEvents().Subscribe(d, d.onWindowEvent)
+	case !enabled && d.subscribed:
+		d.sway.WindowEvents().Unsubscribe(d)
+		d.dnd.SetAuto(false)
+	}
+
+	d.subscribed = enabled
+
+	if enabled {
+		go d.evaluate()
+	}
+}
+
+func (d *DNDAuto) onWindowEvent(event sway.WindowEvent) {
+	switch event.Change {
+	case sway.WindowFullscreen, sway.WindowFocus, sway.WindowClose, sway.WindowNew, sway.WindowMove:
+		d.evaluate()
+	case sway.WindowTitle:
+		d.mu.Lock()
+		byWindow := len(d.windows) > 0
+		d.mu.Unlock()
+
+		// Titles change often, they only matter for windows criteria
+		if byWindow {
+			d.evaluate()
+		}
+	}
+}
+
+func (d *DNDAuto) evaluate() {
+	d.mu.Lock()
+	enabled := d.subscribed
+	d.mu.Unlock()
+
+	if !enabled {
+		return
+	}
+
+	windows, workspaces, err := d.sway.WindowsWithWorkspaces()
+	if err != nil {
+		if !errors.Is(err, sway.ErrNotConnected) {
+			common.LogError("Failed to get windows for automatic do-not-disturb mode", err)
+		}
+
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.subscribed {
+		return
+	}
+
+	d.dnd.SetAuto(d.unsafeMatch(windows, workspaces))
+}
+
+func (d *DNDAuto) unsafeMatch(windows []*sway.Node, workspaces map[int64]string) bool {
+	for _, win := range windows {
+		if d.fullscreen && sway.IsFullscreen(win) {
+			return true
+		}
+
+		for _, id := range d.windows {
+			if id.MatchWindowInWorkspace(win, workspaces[win.ID]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,299 @@
+package modules
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+)
+
+type focusEntry struct {
+	id    int64
+	appID string
+	title string
+	// workspace is only set when listing, because workspaces may have been
+	// renamed since the window was focused
+	workspace string
+}
+
+// FocusHistory keeps the windows in most recently used order. The order per
+// workspace is the global order restricted to the windows of the workspace.
+type FocusHistory struct {
+	sway *sway.Client
+	stop func()
+
+	mu           sync.Mutex
+	cycleTimeout time.Duration
+	entries      []focusEntry
+
+	cycling      bool
+	cycleEntries []focusEntry
+	cycleIndex   int
+	cycleTimer   *time.Timer
+}
+
+func NewFocusHistory(conf *config.FocusHistory, swayClient *sway.Client) *FocusHistory {
+	f := &FocusHistory{
+		sway: swayClient,
+	}
+
+	f.reloadConfig(conf)
+	f.stop = conf.ListenReload(f.reloadConfig)
+
+	f.sway.WindowEvents().Subscribe(f, f.onWindowEvent)
+
+	f.sway.OnConnected(f.init)
+
+	return f
+}
+
+func (f *FocusHistory) Stop() {
+	f.sway.WindowEvents().Unsubscribe(f)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.stop != nil {
+		f.stop()
+		f.stop = nil
+	}
+
+	if f.cycleTimer != nil {
+		f.cycleTimer.Stop()
+	}
+
+	f.cycling = false
+}
+
+func (f *FocusHistory) reloadConfig(conf *config.FocusHistory) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.cycleTimeout = conf.CycleTimeout
+}
+
+// init adds the windows which existed before swaypanion was started, the
+// focused one first.
+func (f *FocusHistory) init() {
+	windows, err := f.sway.Windows()
+	if err != nil {
+		common.LogError("Failed to get windows for focus history", err)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, window := range windows {
+		if f.unsafeIndex(window.ID) != -1 {
+			continue
+		}
+
+		entry := focusEntry{
+			id:    window.ID,
+			appID: sway.AppName(window),
+			title: window.Name,
+		}
+
+		if window.Focused {
+			f.entries = slices.Insert(f.entries, 0, entry)
+		} else {
+			f.entries = append(f.entries, entry)
+		}
+	}
+}
+
+func (f *FocusHistory) onWindowEvent(event sway.WindowEvent) {
+	window := event.Container
+
+	switch event.Change {
+	case sway.WindowNew:
+		f.mu.Lock()
+		if f.unsafeIndex(window.ID) == -1 {
+			f.entries = append(f.entries, focusEntry{
+				id:    window.ID,
+				appID: sway.AppName(&window),
+				title: window.Name,
+			})
+		}
+		f.mu.Unlock()
+	case sway.WindowFocus:
+		f.mu.Lock()
+		f.unsafeUpdate(window, !f.cycling)
+		f.mu.Unlock()
+	case sway.WindowTitle:
+		f.mu.Lock()
+		if i := f.unsafeIndex(window.ID); i != -1 {
+			f.entries[i].title = window.Name
+		}
+		f.mu.Unlock()
+	case sway.WindowClose:
+		f.mu.Lock()
+		if i := f.unsafeIndex(window.ID); i != -1 {
+			f.entries = slices.Delete(f.entries, i, i+1)
+		}
+		f.mu.Unlock()
+	}
+}
+
+func (f *FocusHistory) unsafeUpdate(window sway.Node, toFront bool) {
+	entry := focusEntry{
+		id:    window.ID,
+		appID: sway.AppName(&window),
+		title: window.Name,
+	}
+
+	i := f.unsafeIndex(window.ID)
+
+	switch {
+	case i == -1:
+		if toFront {
+			f.entries = slices.Insert(f.entries, 0, entry)
+		} else {
+			f.entries = append(f.entries, entry)
+		}
+	case toFront:
+		f.entries = slices.Delete(f.entries, i, i+1)
+		f.entries = slices.Insert(f.entries, 0, entry)
+	default:
+		f.entries[i] = entry
+	}
+}
+
+func (f *FocusHistory) unsafeIndex(id int64) int {
+	return slices.IndexFunc(f.entries, func(e focusEntry) bool {
+		return e.id == id
+	})
+}
+
+// windowsWorkspaces returns the name of the workspace containing each window,
+// indexed by window ID. If scope is "workspace", only the windows of the
+// focused workspace are returned.
+func (f *FocusHistory) windowsWorkspaces(scope string) (map[int64]string, error) {
+	if scope != "workspace" {
+		return f.sway.WindowsWorkspaces()
+	}
+
+	rank, workspaces, err := f.sway.CurrentWorkspace()
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := workspaces[rank]
+	windows := sway.Windows(workspace)
+	result := make(map[int64]string, len(windows))
+
+	for _, window := range windows {
+		result[window.ID] = workspace.Name
+	}
+
+	return result, nil
+}
+
+// unsafeList returns the entries of the windows in workspaces, with their
+// workspace names set.
+func (f *FocusHistory) unsafeList(workspaces map[int64]string) []focusEntry {
+	entries := make([]focusEntry, 0, len(workspaces))
+
+	for _, entry := range f.entries {
+		if workspace, ok := workspaces[entry.id]; ok {
+			entry.workspace = workspace
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func (f *FocusHistory) list(scope string) ([]focusEntry, error) {
+	workspaces, err := f.windowsWorkspaces(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.unsafeList(workspaces), nil
+}
+
+func (f *FocusHistory) focusPrevious(scope string) {
+	entries, err := f.list(scope)
+	if err != nil {
+		common.LogError("Failed to get workspaces", err)
+		return
+	}
+
+	if len(entries) < 2 {
+		return
+	}
+
+	if err := f.focus(entries[1].id); err != nil {
+		common.LogError("Failed to focus previous window", err)
+	}
+}
+
+// cycle focuses the next window in the history. The history is not updated
+// while cycling, the selected window becomes the most recently used one
+// when cycle has not been called again during the configured timeout.
+func (f *FocusHistory) cycle(scope string) {
+	workspaces, err := f.windowsWorkspaces(scope)
+	if err != nil {
+		common.LogError("Failed to get workspaces", err)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.cycling {
+		f.cycleTimer.Reset(f.cycleTimeout)
+	} else {
+		f.cycling = true
+		f.cycleEntries = f.unsafeList(workspaces)
+		f.cycleIndex = 0
+		f.cycleTimer = time.AfterFunc(f.cycleTimeout, f.commitCycle)
+	}
+
+	if len(f.cycleEntries) < 2 {
+		return
+	}
+
+	f.cycleIndex = (f.cycleIndex + 1) % len(f.cycleEntries)
+
+	if err := f.focus(f.cycleEntries[f.cycleIndex].id); err != nil {
+		common.LogError("Failed to focus window", err)
+	}
+}
+
+func (f *FocusHistory) commitCycle() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.cycling {
+		return
+	}
+
+	f.cycling = false
+
+	if len(f.cycleEntries) == 0 {
+		return
+	}
+
+	selected := f.cycleEntries[f.cycleIndex].id
+	f.cycleEntries = nil
+
+	if i := f.unsafeIndex(selected); i > 0 {
+		entry := f.entries[i]
+		f.entries = slices.Delete(f.entries, i, i+1)
+		f.entries = slices.Insert(f.entries, 0, entry)
+	}
+}
+
+func (f *FocusHistory) focus(id int64) error {
+	return f.sway.RunCommand(fmt.Sprintf("[con_id=%d] focus", id))
+}
@@ -0,0 +1,62 @@
+package modules
+
+import (
+	"strconv"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+)
+
+func (e focusEntry) message() socket.Message {
+	return socket.Message{
+		Command: windowLabel,
+		Value:   strconv.FormatInt(e.id, 10),
+		Complement: []string{
+			"App: " + e.appID,
+			"Title: " + e.title,
+			"Workspace: " + e.workspace,
+		},
+	}
+}
+
+func (f *FocusHistory) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		f.socketFocusPrevious, windowLabel+" focus-previous", "Focus the previously focused window", `"workspace" to stay in current workspace`,
+		f.socketList, windowLabel+" mru list", "List windows, most recently focused first", `"workspace" to list current workspace only`,
+		f.socketCycle, windowLabel+" mru cycle", "Focus the next window in most recently focused order, selection is committed after a timeout", `"workspace" to stay in current workspace`,
+	)
+}
+
+func (f *FocusHistory) socketFocusPrevious(_ *socketserver.Connection, value string, _ []string) {
+	f.focusPrevious(value)
+}
+
+func (f *FocusHistory) socketList(conn *socketserver.Connection, value string, _ []string) {
+	entries, err := f.list(value)
+	if err != nil {
+		common.LogError("Failed to get workspaces", err)
+		conn.SendError("failed to get workspaces")
+
+		return
+	}
+
+	if len(entries) == 0 {
+		if err := conn.SendString(windowLabel, "no window"); err != nil {
+			common.LogError("Failed to send windows list", err)
+		}
+
+		return
+	}
+
+	for _, entry := range entries {
+		if err := conn.Send(entry.message()); err != nil {
+			common.LogError("Failed to send windows list", err)
+			return
+		}
+	}
+}
+
+func (f *FocusHistory) socketCycle(_ *socketserver.Connection, value string, _ []string) {
+	f.cycle(value)
+}
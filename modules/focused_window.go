@@ -0,0 +1,106 @@
+package modules
+
+import (
+	"errors"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/sway"
+)
+
+type FocusedWindowState struct {
+	ID         int64
+	AppID      string
+	Class      string
+	Title      string
+	Workspace  string
+	Floating   bool
+	Fullscreen bool
+}
+
+func (f FocusedWindowState) Equal(other FocusedWindowState) bool {
+	return f == other
+}
+
+// FocusedWindow publishes the focused window each time it changes, or each
+// time one of its properties changes.
+type FocusedWindow struct {
+	sway          *sway.Client
+	subscriptions *common.Pubsub[FocusedWindowState]
+}
+
+func NewFocusedWindow(swayClient *sway.Client) *FocusedWindow {
+	f := &FocusedWindow{
+		sway:          swayClient,
+		subscriptions: common.NewPubsub[FocusedWindowState](),
+	}
+
+	f.sway.WindowEvents().Subscribe(f, f.onWindowEvent)
+	f.sway.WorkspaceEvents().Subscribe(f, f.onWorkspaceEvent)
+
+	return f
+}
+
+func (f *FocusedWindow) Stop() {
+	f.sway.WindowEvents().Unsubscribe(f)
+	f.sway.WorkspaceEvents().Unsubscribe(f)
+}
+
+func (f *FocusedWindow) onWindowEvent(event sway.WindowEvent) {
+	switch event.Change {
+	case sway.WindowTitle:
+		// Title changes of other windows do not change the state
+		if event.Container.Focused {
+			f.refresh()
+		}
+	case sway.WindowFocus, sway.WindowFloating, sway.WindowFullscreen, sway.WindowMove, sway.WindowClose:
+		f.refresh()
+	}
+}
+
+func (f *FocusedWindow) onWorkspaceEvent(event sway.WorkspaceEvent) {
+	switch event.Change {
+	case sway.WorkspaceFocus, sway.WorkspaceRename:
+		f.refresh()
+	}
+}
+
+// refresh publishes the focused window. An empty state is published when no
+// window is focused, for example on an empty workspace.
+func (f *FocusedWindow) refresh() (FocusedWindowState, error) {
+	window, _, workspace, err := f.sway.FocusedWindow()
+	if err != nil {
+		if errors.Is(err, sway.ErrCurrentWindowNotFound) {
+			f.subscriptions.Publish(FocusedWindowState{})
+			return FocusedWindowState{}, nil
+		}
+
+		if !errors.Is(err, sway.ErrNotConnected) {
+			common.LogError("Failed to get focused window", err)
+		}
+
+		return FocusedWindowState{}, err
+	}
+
+	state := FocusedWindowState{
+		ID:         window.ID,
+		Title:      window.Name,
+		Floating:   sway.IsFloating(window),
+		Fullscreen: sway.IsFullscreen(window),
+	}
+
+	if window.AppID != nil {
+		state.AppID = *window.AppID
+	}
+
+	if window.WindowProperties != nil {
+		state.Class = window.WindowProperties.Class
+	}
+
+	if workspace != nil {
+		state.Workspace = workspace.Name
+	}
+
+	f.subscriptions.Publish(state)
+
+	return state, nil
+}
@@ -0,0 +1,75 @@
+package modules
+
+import (
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+)
+
+func (f FocusedWindowState) message() socket.Message {
+	if f.ID == 0 {
+		return socket.Message{
+			Command: windowLabel + " focused",
+			Value:   "none",
+		}
+	}
+
+	return socket.Message{
+		Command: windowLabel + " focused",
+		Value:   strconv.FormatInt(f.ID, 10),
+		Complement: []string{
+			"App: " + f.AppID,
+			"Class: " + f.Class,
+			"Title: " + f.Title,
+			"Workspace: " + f.Workspace,
+			"Floating: " + strconv.FormatBool(f.Floating),
+			"Fullscreen: " + strconv.FormatBool(f.Fullscreen),
+		},
+	}
+}
+
+func (f *FocusedWindow) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		f.socketGet, windowLabel+" focused", "Get focused window",
+		f.socketGet, windowLabel+" focused get", "Get focused window",
+		f.socketSubscribe, windowLabel+" focused subscribe", "Get focused window each time it changes",
+		f.socketUnsubscribe, windowLabel+" focused unsubscribe", "Stop getting focused window on change",
+	)
+}
+
+func (f *FocusedWindow) socketGet(conn *socketserver.Connection, _ string, _ []string) {
+	state, err := f.refresh()
+	if err != nil {
+		conn.SendError("failed to get focused window")
+		return
+	}
+
+	if err := conn.Send(state.message()); err != nil {
+		common.LogError("Failed to send focused window", err)
+	}
+}
+
+func (f *FocusedWindow) socketSubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	// The focused window is only published on sway events, it must be
+	// refreshed for the initial value
+	f.refresh()
+
+	f.subscriptions.Subscribe(conn, true, func(value FocusedWindowState) {
+		if err := conn.Send(value.message()); err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				f.subscriptions.Unsubscribe(conn)
+				return
+			}
+
+			common.LogError("Failed to send subscribed focused window", err)
+		}
+	})
+}
+
+func (f *FocusedWindow) socketUnsubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	f.subscriptions.Unsubscribe(conn)
+}
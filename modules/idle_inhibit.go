@@ -0,0 +1,406 @@
+package modules
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+)
+
+const mprisPrefix = "org.mpris.MediaPlayer2."
+
+type IdleInhibitState struct {
+	Manual bool
+	Auto   bool
+}
+
+func (i IdleInhibitState) Equal(other IdleInhibitState) bool {
+	return i.Manual == other.Manual && i.Auto == other.Auto
+}
+
+func (i IdleInhibitState) Enabled() bool {
+	return i.Manual || i.Auto
+}
+
+// inhibitor is an active idle inhibition, which lasts until it is released.
+type inhibitor interface {
+	release() error
+}
+
+type IdleInhibit struct {
+	sway          *sway.Client
+	subscriptions *common.Pubsub[IdleInhibitState]
+	auto          *common.Pubsub[common.Bool]
+
+	stop func()
+
+	mu             sync.Mutex
+	state          IdleInhibitState
+	method         config.IdleInhibitMethod
+	inhibitor      inhibitor
+	autoSubscribed bool
+	fullscreen     bool
+	mediaPlayers   []string
+	windows        []config.WindowIdentification
+}
+
+func NewIdleInhibit(conf *config.IdleInhibit, swayClient *sway.Client) *IdleInhibit {
+	i := &IdleInhibit{
+		sway:          swayClient,
+		subscriptions: common.NewPubsub[IdleInhibitState](),
+		auto:          common.NewPubsub[common.Bool](),
+	}
+
+	i.reloadConfig(conf)
+	i.stop = conf.ListenReload(i.reloadConfig)
+
+	swayClient.WindowEvents().Subscribe(&i.inhibitor, i.onWindowEvent)
+
+	return i
+}
+
+func (i *IdleInhibit) Stop() {
+	i.sway.WindowEvents().Unsubscribe(&i.inhibitor)
+
+	i.mu.Lock()
+
+	if i.stop != nil {
+		i.stop()
+		i.stop = nil
+	}
+
+	wasSubscribed := i.autoSubscribed
+	i.autoSubscribed = false
+
+	i.unsafeRelease()
+
+	i.mu.Unlock()
+
+	if wasSubscribed {
+		i.auto.Unsubscribe(i)
+	}
+}
+
+func (i *IdleInhibit) reloadConfig(conf *config.IdleInhibit) {
+	i.mu.Lock()
+
+	if i.method != conf.Method {
+		// The inhibition must be taken again with the new method
+		i.unsafeRelease()
+		i.method = conf.Method
+		i.unsafeApply()
+	}
+
+	i.fullscreen = conf.Fullscreen
+	i.mediaPlayers = make([]string, len(conf.MediaPlayers))
+	copy(i.mediaPlayers, conf.MediaPlayers)
+	i.windows = make([]config.WindowIdentification, len(conf.Windows))
+	copy(i.windows, conf.Windows)
+
+	enabled := i.fullscreen || len(i.mediaPlayers) > 0 || len(i.windows) > 0
+	wasSubscribed := i.autoSubscribed
+	i.autoSubscribed = enabled
+
+	i.mu.Unlock()
+
+	// The polling function locks the mutex, the subscriptions must be
+	// handled after it has been released.
+	i.auto.Reconfigure(common.Config[common.Bool]{
+		PollInterval: conf.PollInterval,
+		PollFn:       i.poll,
+	})
+
+	switch {
+	case enabled && !wasSubscribed:
+		i.auto.Subscribe(i, true, func(auto common.Bool) {
+			i.setAuto(bool(auto))
+		})
+	case !enabled && wasSubscribed:
+		i.auto.Unsubscribe(i)
+		i.setAuto(false)
+	}
+}
+
+func (i *IdleInhibit) get() IdleInhibitState {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.state
+}
+
+func (i *IdleInhibit) on() IdleInhibitState {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.state.Manual = true
+
+	return i.unsafeApply()
+}
+
+func (i *IdleInhibit) off() IdleInhibitState {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.state.Manual = false
+
+	return i.unsafeApply()
+}
+
+func (i *IdleInhibit) toggle() IdleInhibitState {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.state.Manual = !i.state.Manual
+
+	return i.unsafeApply()
+}
+
+func (i *IdleInhibit) setAuto(auto bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.state.Auto = auto
+
+	i.unsafeApply()
+}
+
+// unsafeApply takes or releases the inhibition according to the current
+// state, then publishes the state.
+func (i *IdleInhibit) unsafeApply() IdleInhibitState {
+	switch {
+	case i.state.Enabled() && i.inhibitor == nil:
+		inh, err := i.unsafeInhibit()
+		if err != nil {
+			common.LogError("Failed to inhibit idleness", err)
+		} else {
+			i.inhibitor = inh
+		}
+	case !i.state.Enabled() && i.inhibitor != nil:
+		i.unsafeRelease()
+	}
+
+	i.subscriptions.Publish(i.state)
+
+	return i.state
+}
+
+func (i *IdleInhibit) unsafeInhibit() (inhibitor, error) {
+	switch i.method {
+	case config.IdleInhibitMethodSway:
+		return newSwayInhibitor(i.sway)
+	default:
+		return newLogindInhibitor()
+	}
+}
+
+func (i *IdleInhibit) unsafeRelease() {
+	if i.inhibitor == nil {
+		return
+	}
+
+	if err := i.inhibitor.release(); err != nil {
+		common.LogError("Failed to release idle inhibition", err)
+	}
+
+	i.inhibitor = nil
+}
+
+// onWindowEvent applies the sway inhibition to new windows, so that it
+// survives the windows which were open when it was taken.
+func (i *IdleInhibit) onWindowEvent(event sway.WindowEvent) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	inh, ok := i.inhibitor.(*swayInhibitor)
+	if !ok {
+		return
+	}
+
+	switch event.Change {
+	case sway.WindowNew:
+		if err := inh.add(&event.Container); err != nil {
+			common.LogError("Failed to inhibit idleness on new window", err)
+		}
+	case sway.WindowClose:
+		delete(inh.windows, event.Container.ID)
+	}
+}
+
+func (i *IdleInhibit) poll() (common.Bool, bool) {
+	windows, workspaces, err := i.sway.WindowsWithWorkspaces()
+	if err != nil {
+		if !errors.Is(err, sway.ErrNotConnected) {
+			common.LogError("Failed to get windows for automatic idle inhibition", err)
+		}
+
+		return false, false
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, win := range windows {
+		if i.fullscreen && sway.IsFullscreen(win) {
+			return true, true
+		}
+
+		for _, id := range i.windows {
+			if id.MatchWindowInWorkspace(win, workspaces[win.ID]) {
+				return true, true
+			}
+		}
+	}
+
+	if len(i.mediaPlayers) > 0 {
+		return common.Bool(i.unsafeMediaPlaying()), true
+	}
+
+	return false, true
+}
+
+// unsafeMediaPlaying checks if one of the configured MPRIS players is
+// currently playing. The configured players are expected to be video players,
+// as MPRIS does not expose the kind of media being played. Player names may
+// include an instance suffix, as in "org.mpris.MediaPlayer2.vlc.instance1234".
+func (i *IdleInhibit) unsafeMediaPlaying() bool {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		common.LogError("Failed to connect to DBus", err)
+		return false
+	}
+
+	var names []string
+	if err := conn.BusObject().Call("org.freedesktop.DBus.ListNames", 0).Store(&names); err != nil {
+		common.LogError("Failed to list DBus names", err)
+		return false
+	}
+
+	for _, name := range names {
+		playerName, ok := strings.CutPrefix(name, mprisPrefix)
+		if !ok {
+			continue
+		}
+
+		if !slices.ContainsFunc(i.mediaPlayers, func(configured string) bool {
+			return playerName == configured || strings.HasPrefix(playerName, configured+".")
+		}) {
+			continue
+		}
+
+		status, err := conn.Object(name, "/org/mpris/MediaPlayer2").
+			GetProperty("org.mpris.MediaPlayer2.Player.PlaybackStatus")
+		if err != nil {
+			continue
+		}
+
+		if statusValue, _ := status.Value().(string); statusValue == "Playing" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// logindInhibitor holds a logind "idle" inhibitor lock, which is released
+// when its file descriptor is closed.
+type logindInhibitor struct {
+	file *os.File
+}
+
+func newLogindInhibitor() (*logindInhibitor, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+
+	var fd dbus.UnixFD
+
+	if err := conn.Object("org.freedesktop.login1", "/org/freedesktop/login1").Call(
+		"org.freedesktop.login1.Manager.Inhibit", 0,
+		"idle", "Swaypanion", "Idle inhibition requested", "block",
+	).Store(&fd); err != nil {
+		return nil, err
+	}
+
+	return &logindInhibitor{
+		file: os.NewFile(uintptr(fd), "swaypanion-idle-inhibitor"),
+	}, nil
+}
+
+func (l *logindInhibitor) release() error {
+	return l.file.Close()
+}
+
+// swayInhibitor sets "inhibit_idle open" on the windows which have no
+// inhibit_idle rule from the sway configuration. Releasing it only resets
+// these windows, so that the rules of the other windows are kept.
+type swayInhibitor struct {
+	sway    *sway.Client
+	windows map[int64]struct{}
+}
+
+func newSwayInhibitor(swayClient *sway.Client) (*swayInhibitor, error) {
+	windows, err := swayClient.Windows()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &swayInhibitor{
+		sway:    swayClient,
+		windows: map[int64]struct{}{},
+	}
+
+	for _, window := range windows {
+		if err := s.add(window); err != nil {
+			if err := s.release(); err != nil {
+				common.LogError("Failed to release partial idle inhibition", err)
+			}
+
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// add inhibits idleness on the window, unless it has its own inhibit_idle
+// rule.
+func (s *swayInhibitor) add(window *sway.Node) error {
+	if user := window.IdleInhibitors.User; user != "" && user != "none" {
+		return nil
+	}
+
+	if err := s.sway.RunCommand(
+		"[con_id=" + strconv.FormatInt(window.ID, 10) + "] inhibit_idle open",
+	); err != nil {
+		return err
+	}
+
+	s.windows[window.ID] = struct{}{}
+
+	return nil
+}
+
+func (s *swayInhibitor) release() error {
+	var errs []error
+
+	for id := range s.windows {
+		if err := s.sway.RunCommand(
+			"[con_id=" + strconv.FormatInt(id, 10) + "] inhibit_idle none",
+		); err != nil {
+			errs = append(errs, err)
+		}
+
+		delete(s.windows, id)
+	}
+
+	return errors.Join(errs...)
+}
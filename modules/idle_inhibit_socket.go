@@ -0,0 +1,82 @@
+package modules
+
+import (
+	"errors"
+	"net"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+)
+
+const idleInhibitLabel = "idle-inhibit"
+
+func (i IdleInhibitState) message() socket.Message {
+	msg := socket.Message{
+		Command: idleInhibitLabel,
+		Value:   "off",
+	}
+
+	if i.Enabled() {
+		msg.Value = "on"
+	}
+
+	if i.Auto {
+		msg.Complement = append(msg.Complement, "Automatic")
+	}
+
+	return msg
+}
+
+func (i *IdleInhibit) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		i.socketStatus, idleInhibitLabel, "Get current idle inhibition",
+		i.socketStatus, idleInhibitLabel+" status", "Get current idle inhibition",
+		i.socketOn, idleInhibitLabel+" on", "Inhibit idleness",
+		i.socketOff, idleInhibitLabel+" off", "Stop inhibiting idleness, unless automatically inhibited",
+		i.socketToggle, idleInhibitLabel+" toggle", "Toggle idle inhibition",
+		i.socketSubscribe, idleInhibitLabel+" subscribe", "Get idle inhibition each time it changes",
+		i.socketUnsubscribe, idleInhibitLabel+" unsubscribe", "Stop getting idle inhibition on change",
+	)
+}
+
+func (i *IdleInhibit) socketStatus(conn *socketserver.Connection, _ string, _ []string) {
+	if err := conn.Send(i.get().message()); err != nil {
+		common.LogError("Failed to send idle inhibition", err)
+	}
+}
+
+func (i *IdleInhibit) socketOn(conn *socketserver.Connection, _ string, _ []string) {
+	if err := conn.Send(i.on().message()); err != nil {
+		common.LogError("Failed to send idle inhibition", err)
+	}
+}
+
+func (i *IdleInhibit) socketOff(conn *socketserver.Connection, _ string, _ []string) {
+	if err := conn.Send(i.off().message()); err != nil {
+		common.LogError("Failed to send idle inhibition", err)
+	}
+}
+
+func (i *IdleInhibit) socketToggle(conn *socketserver.Connection, _ string, _ []string) {
+	if err := conn.Send(i.toggle().message()); err != nil {
+		common.LogError("Failed to send idle inhibition", err)
+	}
+}
+
+func (i *IdleInhibit) socketSubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	i.subscriptions.Subscribe(conn, true, func(value IdleInhibitState) {
+		if err := conn.Send(value.message()); err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				i.subscriptions.Unsubscribe(conn)
+				return
+			}
+
+			common.LogError("Failed to send subscribed idle inhibition", err)
+		}
+	})
+}
+
+func (i *IdleInhibit) socketUnsubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	i.subscriptions.Unsubscribe(conn)
+}
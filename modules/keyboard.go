@@ -0,0 +1,256 @@
+package modules
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+)
+
+var (
+	errNoKeyboard         = errors.New("no keyboard found")
+	errInvalidLayoutIndex = errors.New("invalid layout index")
+)
+
+type KeyboardLayout struct {
+	Keyboard string
+	Index    int64
+	Name     string
+	Names    []string
+}
+
+func (k KeyboardLayout) Equal(other KeyboardLayout) bool {
+	return k.Keyboard == other.Keyboard &&
+		k.Index == other.Index &&
+		k.Name == other.Name &&
+		slices.Equal(k.Names, other.Names)
+}
+
+func keyboardLayout(input sway.Input) KeyboardLayout {
+	layout := KeyboardLayout{
+		Keyboard: input.Identifier,
+		Names:    input.XKBLayoutNames,
+	}
+
+	if input.XKBActiveLayoutIndex != nil {
+		layout.Index = *input.XKBActiveLayoutIndex
+	}
+
+	if input.XKBActiveLayoutName != nil {
+		layout.Name = *input.XKBActiveLayoutName
+	}
+
+	return layout
+}
+
+// Keyboard reports and switches the keyboard layout. When the layout is
+// remembered per window, the layout index is stored each time it changes and
+// restored when the window gets the focus again.
+type Keyboard struct {
+	sway          *sway.Client
+	subscriptions *common.Pubsub[KeyboardLayout]
+
+	stop func()
+
+	mu             sync.Mutex
+	identifier     string
+	perWindow      bool
+	current        KeyboardLayout
+	focusedWindow  int64
+	windowsLayouts map[int64]int64
+}
+
+func NewKeyboard(conf *config.Keyboard, swayClient *sway.Client) *Keyboard {
+	k := &Keyboard{
+		sway:           swayClient,
+		subscriptions:  common.NewPubsub[KeyboardLayout](),
+		windowsLayouts: map[int64]int64{},
+	}
+
+	k.reloadConfig(conf)
+	k.stop = conf.ListenReload(k.reloadConfig)
+
+	k.sway.InputEvents().Subscribe(k, k.onInputEvent)
+	k.sway.WindowEvents().Subscribe(k, k.onWindowEvent)
+
+	k.sway.OnConnected(k.init)
+
+	return k
+}
+
+func (k *Keyboard) Stop() {
+	k.sway.InputEvents().Unsubscribe(k)
+	k.sway.WindowEvents().Unsubscribe(k)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.stop != nil {
+		k.stop()
+		k.stop = nil
+	}
+}
+
+// init gets the current layout once connected to sway.
+func (k *Keyboard) init() {
+	if _, err := k.refresh(); err != nil && !errors.Is(err, errNoKeyboard) {
+		common.LogError("Failed to get keyboard layout", err)
+	}
+}
+
+func (k *Keyboard) reloadConfig(conf *config.Keyboard) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.identifier = conf.Identifier
+	k.perWindow = conf.PerWindow
+
+	if !k.perWindow {
+		clear(k.windowsLayouts)
+	}
+}
+
+// keyboards returns the keyboards with layouts, only keeping the configured
+// one if an identifier is configured.
+func (k *Keyboard) keyboards() ([]sway.Input, error) {
+	inputs, err := k.sway.Keyboards()
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	identifier := k.identifier
+	k.mu.Unlock()
+
+	keyboards := make([]sway.Input, 0, len(inputs))
+
+	for _, input := range inputs {
+		if len(input.XKBLayoutNames) == 0 {
+			continue
+		}
+
+		if identifier != "" && input.Identifier != identifier {
+			continue
+		}
+
+		keyboards = append(keyboards, input)
+	}
+
+	return keyboards, nil
+}
+
+// refresh publishes the layout of the first keyboard.
+func (k *Keyboard) refresh() (KeyboardLayout, error) {
+	keyboards, err := k.keyboards()
+	if err != nil {
+		return KeyboardLayout{}, err
+	}
+
+	if len(keyboards) == 0 {
+		return KeyboardLayout{}, errNoKeyboard
+	}
+
+	layout := keyboardLayout(keyboards[0])
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.current = layout
+
+	if k.perWindow && k.focusedWindow != 0 {
+		k.windowsLayouts[k.focusedWindow] = layout.Index
+	}
+
+	k.subscriptions.Publish(layout)
+
+	return layout, nil
+}
+
+func (k *Keyboard) onInputEvent(event sway.InputEvent) {
+	if event.Input.Type != sway.InputTypeKeyboard {
+		return
+	}
+
+	switch event.Change {
+	case sway.InputAdded, sway.InputRemoved, sway.InputXKBKeymap, sway.InputXKBLayout:
+		if _, err := k.refresh(); err != nil && !errors.Is(err, errNoKeyboard) {
+			common.LogError("Failed to get keyboard layout", err)
+		}
+	}
+}
+
+func (k *Keyboard) onWindowEvent(event sway.WindowEvent) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	switch event.Change {
+	case sway.WindowClose:
+		delete(k.windowsLayouts, event.Container.ID)
+	case sway.WindowFocus:
+		k.focusedWindow = event.Container.ID
+
+		if !k.perWindow {
+			return
+		}
+
+		index, ok := k.windowsLayouts[event.Container.ID]
+		if !ok {
+			k.windowsLayouts[event.Container.ID] = k.current.Index
+			return
+		}
+
+		if index == k.current.Index {
+			return
+		}
+
+		if err := k.sway.RunCommand(k.unsafeSwitchCommand(strconv.FormatInt(index, 10))); err != nil {
+			common.LogError("Failed to restore window keyboard layout", err)
+		}
+	}
+}
+
+func (k *Keyboard) unsafeSwitchCommand(target string) string {
+	input := "type:keyboard"
+	if k.identifier != "" {
+		input = k.identifier
+	}
+
+	return "input " + input + " xkb_switch_layout " + target
+}
+
+func (k *Keyboard) switchLayout(target string) (KeyboardLayout, error) {
+	k.mu.Lock()
+	command := k.unsafeSwitchCommand(target)
+	k.mu.Unlock()
+
+	if err := k.sway.RunCommand(command); err != nil {
+		return KeyboardLayout{}, err
+	}
+
+	return k.refresh()
+}
+
+func (k *Keyboard) next() (KeyboardLayout, error) {
+	return k.switchLayout("next")
+}
+
+func (k *Keyboard) prev() (KeyboardLayout, error) {
+	return k.switchLayout("prev")
+}
+
+func (k *Keyboard) set(index int64) (KeyboardLayout, error) {
+	layout, err := k.refresh()
+	if err != nil {
+		return KeyboardLayout{}, err
+	}
+
+	if index < 0 || index >= int64(len(layout.Names)) {
+		return KeyboardLayout{}, errInvalidLayoutIndex
+	}
+
+	return k.switchLayout(strconv.FormatInt(index, 10))
+}
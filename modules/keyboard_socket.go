@@ -0,0 +1,132 @@
+package modules
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+)
+
+const keyboardLabel = "keyboard"
+
+func (k KeyboardLayout) message() socket.Message {
+	return socket.Message{
+		Command: keyboardLabel,
+		Value:   k.Name,
+		Complement: []string{
+			"Index: " + strconv.FormatInt(k.Index, 10),
+			"Keyboard: " + k.Keyboard,
+			"Layouts: " + strings.Join(k.Names, ", "),
+		},
+	}
+}
+
+func (k *Keyboard) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		k.socketGet, keyboardLabel+" layout", "Get current keyboard layout",
+		k.socketList, keyboardLabel+" layout list", "Get the current layout of each keyboard",
+		k.socketNext, keyboardLabel+" layout next", "Switch to next keyboard layout",
+		k.socketPrev, keyboardLabel+" layout prev", "Switch to previous keyboard layout",
+		k.socketSet, keyboardLabel+" layout set", "Switch to a keyboard layout", "layout index, from 0",
+		k.socketSubscribe, keyboardLabel+" layout subscribe", "Get keyboard layout each time it changes",
+		k.socketUnsubscribe, keyboardLabel+" layout unsubscribe", "Stop getting keyboard layout on change",
+	)
+}
+
+func (k *Keyboard) sendLayout(conn *socketserver.Connection, layout KeyboardLayout, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, errNoKeyboard):
+			conn.SendError("no keyboard found")
+		case errors.Is(err, errInvalidLayoutIndex):
+			conn.SendError("invalid layout index")
+		default:
+			common.LogError("Failed to get or switch keyboard layout", err)
+			conn.SendError("failed to get or switch keyboard layout")
+		}
+
+		return
+	}
+
+	if err := conn.Send(layout.message()); err != nil {
+		common.LogError("Failed to send keyboard layout", err)
+	}
+}
+
+func (k *Keyboard) socketGet(conn *socketserver.Connection, _ string, _ []string) {
+	layout, err := k.refresh()
+	k.sendLayout(conn, layout, err)
+}
+
+func (k *Keyboard) socketList(conn *socketserver.Connection, _ string, _ []string) {
+	keyboards, err := k.keyboards()
+	if err != nil {
+		common.LogError("Failed to get keyboards", err)
+		conn.SendError("failed to get keyboards")
+
+		return
+	}
+
+	if len(keyboards) == 0 {
+		conn.SendError("no keyboard found")
+		return
+	}
+
+	for _, keyboard := range keyboards {
+		if err := conn.Send(keyboardLayout(keyboard).message()); err != nil {
+			common.LogError("Failed to send keyboards layouts", err)
+			return
+		}
+	}
+}
+
+func (k *Keyboard) socketNext(conn *socketserver.Connection, _ string, _ []string) {
+	layout, err := k.next()
+	k.sendLayout(conn, layout, err)
+}
+
+func (k *Keyboard) socketPrev(conn *socketserver.Connection, _ string, _ []string) {
+	layout, err := k.prev()
+	k.sendLayout(conn, layout, err)
+}
+
+func (k *Keyboard) socketSet(conn *socketserver.Connection, value string, _ []string) {
+	if value == "" {
+		conn.SendError("missing layout index")
+		return
+	}
+
+	index, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		conn.SendError("failed to convert argument to int")
+		return
+	}
+
+	layout, err := k.set(index)
+	k.sendLayout(conn, layout, err)
+}
+
+func (k *Keyboard) socketSubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	if _, err := k.refresh(); err != nil && !errors.Is(err, errNoKeyboard) {
+		common.LogError("Failed to get keyboard layout", err)
+	}
+
+	k.subscriptions.Subscribe(conn, true, func(value KeyboardLayout) {
+		if err := conn.Send(value.message()); err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				k.subscriptions.Unsubscribe(conn)
+				return
+			}
+
+			common.LogError("Failed to send subscribed keyboard layout", err)
+		}
+	})
+}
+
+func (k *Keyboard) socketUnsubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	k.subscriptions.Unsubscribe(conn)
+}
@@ -0,0 +1,455 @@
+package modules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+)
+
+const (
+	layoutsDirName   = "layouts"
+	layoutMarkPrefix = "_swaypanion_layout_"
+)
+
+var (
+	errInvalidLayoutName = errors.New("invalid layout name")
+	errEmptyLayout       = errors.New("no window in workspace")
+	errEmptyContainer    = errors.New("container without window in layout")
+)
+
+// layoutNode is a saved container: either a window, identified by the app
+// it belongs to or by its properties, or a container with its layout.
+type layoutNode struct {
+	Layout   string                       `json:"layout,omitempty"`
+	Percent  float64                      `json:"percent,omitempty"`
+	App      string                       `json:"app,omitempty"`
+	Window   *config.WindowIdentification `json:"window,omitempty"`
+	Nodes    []*layoutNode                `json:"nodes,omitempty"`
+	Floating []*layoutNode                `json:"floating,omitempty"`
+
+	mark string
+}
+
+func (l *layoutNode) isWindow() bool {
+	return l.Window != nil
+}
+
+// first returns the first window in the node, which represents the node
+// while the layout is built, or nil if the node contains no tiled window.
+func (l *layoutNode) first() (window *layoutNode, depth int) {
+	node := l
+
+	for !node.isWindow() {
+		if len(node.Nodes) == 0 {
+			return nil, depth
+		}
+
+		node = node.Nodes[0]
+		depth++
+	}
+
+	return node, depth
+}
+
+// prune removes the containers without any tiled window and the floating
+// entries which are not windows, which cannot be restored.
+func (l *layoutNode) prune() {
+	nodes := l.Nodes[:0]
+
+	for _, node := range l.Nodes {
+		node.prune()
+
+		if node.isWindow() || len(node.Nodes) > 0 {
+			nodes = append(nodes, node)
+		}
+	}
+
+	l.Nodes = nodes
+	l.Floating = slices.DeleteFunc(l.Floating, func(node *layoutNode) bool {
+		return !node.isWindow()
+	})
+}
+
+func (l *layoutNode) windows() []*layoutNode {
+	if l.isWindow() {
+		return []*layoutNode{l}
+	}
+
+	var windows []*layoutNode
+
+	for _, node := range l.Nodes {
+		windows = append(windows, node.windows()...)
+	}
+
+	return append(windows, l.Floating...)
+}
+
+// Layouts saves the layout of workspaces and restores them, launching the
+// missing apps.
+type Layouts struct {
+	sway *sway.Client
+	apps *Apps
+}
+
+func NewLayouts(swayClient *sway.Client, apps *Apps) *Layouts {
+	return &Layouts{
+		sway: swayClient,
+		apps: apps,
+	}
+}
+
+func layoutPath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", common.Errorf(name, errInvalidLayoutName)
+	}
+
+	dir, err := common.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir = filepath.Join(dir, layoutsDirName)
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name+".json"), nil
+}
+
+func (l *Layouts) list() ([]string, error) {
+	dir, err := common.StateDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, layoutsDirName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var names []string
+
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+// save writes the layout of the focused workspace.
+func (l *Layouts) save(name string) error {
+	path, err := layoutPath(name)
+	if err != nil {
+		return err
+	}
+
+	rank, workspaces, err := l.sway.CurrentWorkspace()
+	if err != nil {
+		return err
+	}
+
+	root := l.snapshot(workspaces[rank], workspaces[rank].Name)
+	if len(root.windows()) == 0 {
+		return errEmptyLayout
+	}
+
+	content, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0o600)
+}
+
+func (l *Layouts) snapshot(node *sway.Node, workspace string) *layoutNode {
+	saved := &layoutNode{}
+
+	if node.Percent != nil {
+		saved.Percent = *node.Percent
+	}
+
+	if sway.IsWindow(node) {
+		saved.App, saved.Window = l.apps.identify(node, workspace)
+		return saved
+	}
+
+	saved.Layout = string(node.Layout)
+
+	for _, subnode := range node.Nodes {
+		if sub := l.snapshot(subnode, workspace); sub.isWindow() || len(sub.Nodes) > 0 {
+			saved.Nodes = append(saved.Nodes, sub)
+		}
+	}
+
+	for _, subnode := range node.FloatingNodes {
+		for _, window := range sway.Windows(subnode) {
+			saved.Floating = append(saved.Floating, l.snapshot(window, workspace))
+		}
+	}
+
+	return saved
+}
+
+// restore rebuilds a saved layout in the focused workspace. Existing windows
+// are used when they match, missing apps are launched.
+func (l *Layouts) restore(name string) error {
+	path, err := layoutPath(name)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var root layoutNode
+	if err := json.Unmarshal(content, &root); err != nil {
+		return err
+	}
+
+	root.prune()
+
+	rank, workspaces, err := l.sway.CurrentWorkspace()
+	if err != nil {
+		return err
+	}
+
+	workspace := workspaces[rank].Name
+
+	windows := root.windows()
+	if len(windows) == 0 {
+		return errEmptyLayout
+	}
+
+	if err := l.markWindows(windows); err != nil {
+		return err
+	}
+
+	defer l.unmarkWindows(windows)
+
+	for _, window := range windows {
+		if err := l.sway.RunCommand(fmt.Sprintf(
+			`[con_mark="%s"] floating disable, move to workspace "%s"`, window.mark, strings.ReplaceAll(workspace, `"`, `\"`),
+		)); err != nil {
+			return err
+		}
+	}
+
+	if len(root.Nodes) > 0 {
+		if err := l.build(&root); err != nil {
+			return err
+		}
+	}
+
+	for _, window := range root.Floating {
+		if err := l.sway.RunCommand(fmt.Sprintf(`[con_mark="%s"] floating enable`, window.mark)); err != nil {
+			return err
+		}
+	}
+
+	return l.resize(&root)
+}
+
+// markWindows finds or launches the window for each saved window, and marks
+// it.
+func (l *Layouts) markWindows(saved []*layoutNode) error {
+	windows, workspaces, err := l.sway.WindowsWithWorkspaces()
+	if err != nil {
+		return err
+	}
+
+	used := map[int64]bool{}
+
+	for i, window := range saved {
+		window.mark = layoutMarkPrefix + strconv.Itoa(i)
+
+		id, err := l.findOrLaunch(window, windows, workspaces, used)
+		if err != nil {
+			return err
+		}
+
+		used[id] = true
+
+		if err := l.sway.RunCommand(fmt.Sprintf(`[con_id=%d] mark --add "%s"`, id, window.mark)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *Layouts) findOrLaunch(
+	saved *layoutNode, windows []*sway.Node, workspaces map[int64]string, used map[int64]bool,
+) (int64, error) {
+	for _, window := range windows {
+		if !used[window.ID] && saved.Window.MatchWindowInWorkspace(window, workspaces[window.ID]) {
+			return window.ID, nil
+		}
+	}
+
+	if saved.App == "" {
+		return 0, common.Errorf(string(saved.Window.Type)+"="+saved.Window.Match, errNoMatchingWindow)
+	}
+
+	app, err := l.apps.app(saved.App)
+	if err != nil {
+		return 0, err
+	}
+
+	appeared, err := l.apps.start(app)
+	if err != nil {
+		return 0, err
+	}
+
+	return appeared()
+}
+
+func (l *Layouts) unmarkWindows(windows []*layoutNode) {
+	for _, window := range windows {
+		if window.mark == "" {
+			continue
+		}
+
+		if err := l.sway.RunCommand(fmt.Sprintf(`[con_mark="%s"] unmark "%s"`, window.mark, window.mark)); err != nil {
+			common.LogError("Failed to remove layout mark", err)
+		}
+	}
+}
+
+// build arranges the windows of a container: the first window of each child
+// is placed next to the first window of the previous child, the layout is
+// set, then each child container is built in a new container around its
+// first window.
+func (l *Layouts) build(container *layoutNode) error {
+	firsts := make([]*layoutNode, len(container.Nodes))
+
+	for i, node := range container.Nodes {
+		if firsts[i], _ = node.first(); firsts[i] == nil {
+			return errEmptyContainer
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		if err := l.sway.RunCommand(fmt.Sprintf(
+			`[con_mark="%s"] move to mark "%s"`, firsts[i].mark, firsts[i-1].mark,
+		)); err != nil {
+			return err
+		}
+	}
+
+	if container.Layout != "" {
+		if err := l.sway.RunCommand(fmt.Sprintf(
+			`[con_mark="%s"] layout %s`, firsts[0].mark, container.Layout,
+		)); err != nil {
+			return err
+		}
+	}
+
+	for i, node := range container.Nodes {
+		if node.isWindow() {
+			continue
+		}
+
+		if err := l.sway.RunCommand(fmt.Sprintf(`[con_mark="%s"] split vertical`, firsts[i].mark)); err != nil {
+			return err
+		}
+
+		if err := l.build(node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resize applies the saved sizes. Containers are found from their first
+// window, going up in the tree.
+func (l *Layouts) resize(root *layoutNode) error {
+	tree, err := l.sway.Tree()
+	if err != nil {
+		return err
+	}
+
+	parents := map[int64]*sway.Node{}
+	marked := map[string]*sway.Node{}
+
+	var walk func(node *sway.Node)
+	walk = func(node *sway.Node) {
+		for _, mark := range node.Marks {
+			marked[mark] = node
+		}
+
+		for _, subnode := range node.Nodes {
+			parents[subnode.ID] = node
+			walk(subnode)
+		}
+	}
+
+	walk(tree)
+
+	var apply func(container *layoutNode) error
+	apply = func(container *layoutNode) error {
+		var dimension string
+
+		switch container.Layout {
+		case "splith":
+			dimension = "width"
+		case "splitv":
+			dimension = "height"
+		}
+
+		for _, node := range container.Nodes {
+			first, depth := node.first()
+			if first == nil {
+				continue
+			}
+
+			actual := marked[first.mark]
+			for range depth {
+				if actual == nil {
+					break
+				}
+
+				actual = parents[actual.ID]
+			}
+
+			if dimension != "" && actual != nil && node.Percent > 0 {
+				if err := l.sway.RunCommand(fmt.Sprintf(
+					"[con_id=%d] resize set %s %d ppt", actual.ID, dimension, int(math.Round(node.Percent*100)),
+				)); err != nil {
+					return err
+				}
+			}
+
+			if !node.isWindow() {
+				if err := apply(node); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	return apply(root)
+}
@@ -0,0 +1,66 @@
+package modules
+
+import (
+	"strings"
+
+	"github.com/willoma/swaypanion/common"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+)
+
+const layoutLabel = "layout"
+
+func (l *Layouts) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		l.socketList, layoutLabel+" list", "List saved layouts",
+		l.socketSave, layoutLabel+" save", "Save the layout of the focused workspace", "layout name",
+		l.socketRestore, layoutLabel+" restore", "Restore a layout in the focused workspace, launching missing apps", "layout name",
+	)
+}
+
+func (l *Layouts) socketList(conn *socketserver.Connection, _ string, _ []string) {
+	names, err := l.list()
+	if err != nil {
+		common.LogError("Failed to list layouts", err)
+		conn.SendError("failed to list layouts")
+
+		return
+	}
+
+	if len(names) == 0 {
+		if err := conn.SendString(layoutLabel, "no layout"); err != nil {
+			common.LogError("Failed to send layouts", err)
+		}
+
+		return
+	}
+
+	if err := conn.SendString(layoutLabel, strings.Join(names, ", ")); err != nil {
+		common.LogError("Failed to send layouts", err)
+	}
+}
+
+func (l *Layouts) socketSave(conn *socketserver.Connection, value string, _ []string) {
+	if err := l.save(value); err != nil {
+		common.LogError("Failed to save layout", err)
+		conn.SendError("failed to save layout: " + err.Error())
+
+		return
+	}
+
+	if err := conn.SendString(layoutLabel, "saved"); err != nil {
+		common.LogError("Failed to send layout", err)
+	}
+}
+
+func (l *Layouts) socketRestore(conn *socketserver.Connection, value string, _ []string) {
+	if err := l.restore(value); err != nil {
+		common.LogError("Failed to restore layout", err)
+		conn.SendError("failed to restore layout: " + err.Error())
+
+		return
+	}
+
+	if err := conn.SendString(layoutLabel, "restored"); err != nil {
+		common.LogError("Failed to send layout", err)
+	}
+}
@@ -0,0 +1,308 @@
+package modules
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/notification"
+	"github.com/willoma/swaypanion/sway"
+)
+
+var (
+	errUnknownProfile    = errors.New("unknown profile")
+	errProfileMismatch   = errors.New("profile does not match connected outputs")
+	errUnknownDisplay    = errors.New("unknown output")
+	errNoMatchingProfile = errors.New("no matching profile")
+)
+
+// OutputProfiles applies the configured profile matching the connected
+// outputs each time outputs change, the same way kanshi does.
+type OutputProfiles struct {
+	sway     *sway.Client
+	notifier *notification.MessageNotifier
+
+	stop func()
+
+	mu        sync.Mutex
+	autoApply bool
+	profiles  []config.OutputProfile
+	connected string
+	current   string
+}
+
+func NewOutputProfiles(conf *config.Outputs, swayClient *sway.Client, notif *notification.Notification) *OutputProfiles {
+	o := &OutputProfiles{
+		sway:     swayClient,
+		notifier: notif.MessageNotifier(),
+	}
+
+	o.reloadConfig(conf)
+	o.stop = conf.ListenReload(o.reloadConfig)
+
+	o.sway.OutputEvents().Subscribe(o, o.onOutputEvent)
+	o.sway.OnConnected(o.check)
+
+	return o
+}
+
+func (o *OutputProfiles) Stop() {
+	o.sway.OutputEvents().Unsubscribe(o)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.stop != nil {
+		o.stop()
+		o.stop = nil
+	}
+}
+
+func (o *OutputProfiles) reloadConfig(conf *config.Outputs) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.notifier.Reconfigure(conf.Notification)
+	o.autoApply = conf.AutoApply != nil && *conf.AutoApply
+	o.profiles = make([]config.OutputProfile, len(conf.Profiles))
+	copy(o.profiles, conf.Profiles)
+
+	// Profiles may have changed, the matching profile must be applied even
+	// if the connected outputs did not change
+	o.connected = ""
+
+	go o.check()
+}
+
+func (o *OutputProfiles) onOutputEvent(sway.OutputEvent) {
+	o.check()
+}
+
+// check applies the first matching profile if the connected outputs have
+// changed since the last check.
+func (o *OutputProfiles) check() {
+	displays, err := o.sway.Displays()
+	if err != nil {
+		if !errors.Is(err, sway.ErrNotConnected) {
+			common.LogError("Failed to get outputs", err)
+		}
+
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !o.autoApply {
+		return
+	}
+
+	connected := displaysKey(displays)
+	if connected == o.connected {
+		// Applying a profile sends output events, they must be ignored
+		return
+	}
+
+	o.connected = connected
+
+	for _, profile := range o.profiles {
+		if assignment := matchProfile(profile, displays); assignment != nil {
+			if err := o.unsafeApply(profile, assignment); err != nil {
+				common.LogError("Failed to apply output profile "+profile.Name, err)
+			}
+
+			return
+		}
+	}
+
+	o.current = ""
+}
+
+func (o *OutputProfiles) apply(name string) error {
+	displays, err := o.sway.Displays()
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	index := slices.IndexFunc(o.profiles, func(p config.OutputProfile) bool {
+		return p.Name == name
+	})
+	if index == -1 {
+		return errUnknownProfile
+	}
+
+	assignment := matchProfile(o.profiles[index], displays)
+	if assignment == nil {
+		return errProfileMismatch
+	}
+
+	o.connected = displaysKey(displays)
+
+	return o.unsafeApply(o.profiles[index], assignment)
+}
+
+// unsafeApply disables outputs first, so that enabled outputs can take
+// their position.
+func (o *OutputProfiles) unsafeApply(profile config.OutputProfile, assignment []sway.Display) error {
+	var disable, enable []string
+
+	for i, settings := range profile.Outputs {
+		target := `output "` + assignment[i].Name + `"`
+
+		if settings.Enabled != nil && !*settings.Enabled {
+			disable = append(disable, target+" disable")
+			continue
+		}
+
+		command := target + " enable"
+
+		if settings.Mode != "" {
+			command += " mode " + settings.Mode
+		}
+
+		if settings.Position != "" {
+			command += " position " + strings.ReplaceAll(settings.Position, ",", " ")
+		}
+
+		if settings.Scale != 0 {
+			command += " scale " + strconv.FormatFloat(settings.Scale, 'f', -1, 64)
+		}
+
+		if settings.Transform != "" {
+			command += " transform " + settings.Transform
+		}
+
+		enable = append(enable, command)
+	}
+
+	for _, command := range append(disable, enable...) {
+		if err := o.sway.RunCommand(command); err != nil {
+			return err
+		}
+	}
+
+	o.current = profile.Name
+	o.notifier.Notify("Output profile applied: " + profile.Name)
+
+	return nil
+}
+
+func (o *OutputProfiles) currentProfile() (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.current == "" {
+		return "", errNoMatchingProfile
+	}
+
+	return o.current, nil
+}
+
+func (o *OutputProfiles) profileNames() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	names := make([]string, len(o.profiles))
+	for i, profile := range o.profiles {
+		names[i] = profile.Name
+	}
+
+	return names
+}
+
+// toggle enables or disables an output, identified by its name or its
+// description.
+func (o *OutputProfiles) toggle(name string) (sway.Display, error) {
+	displays, err := o.sway.Displays()
+	if err != nil {
+		return sway.Display{}, err
+	}
+
+	index := slices.IndexFunc(displays, func(d sway.Display) bool {
+		return d.Name == name || sway.DisplayDescription(d) == name
+	})
+	if index == -1 {
+		return sway.Display{}, errUnknownDisplay
+	}
+
+	display := displays[index]
+
+	command := `output "` + display.Name + `" enable`
+	if display.Active {
+		command = `output "` + display.Name + `" disable`
+	}
+
+	if err := o.sway.RunCommand(command); err != nil {
+		return sway.Display{}, err
+	}
+
+	display.Active = !display.Active
+
+	return display, nil
+}
+
+// displaysKey identifies a set of connected displays.
+func displaysKey(displays []sway.Display) string {
+	keys := make([]string, len(displays))
+	for i, display := range displays {
+		keys[i] = display.Name + ":" + sway.DisplayDescription(display)
+	}
+
+	slices.Sort(keys)
+
+	return strings.Join(keys, "\n")
+}
+
+func settingsMatch(settings config.OutputSettings, display sway.Display) bool {
+	return settings.Match == "*" ||
+		settings.Match == display.Name ||
+		settings.Match == sway.DisplayDescription(display)
+}
+
+// matchProfile returns the display assigned to each output of the profile,
+// or nil if the connected displays are not exactly the profile outputs.
+func matchProfile(profile config.OutputProfile, displays []sway.Display) []sway.Display {
+	if len(profile.Outputs) != len(displays) {
+		return nil
+	}
+
+	assignment := make([]sway.Display, len(profile.Outputs))
+	used := make([]bool, len(displays))
+
+	var assign func(i int) bool
+	assign = func(i int) bool {
+		if i == len(profile.Outputs) {
+			return true
+		}
+
+		for j, display := range displays {
+			if used[j] || !settingsMatch(profile.Outputs[i], display) {
+				continue
+			}
+
+			used[j] = true
+			assignment[i] = display
+
+			if assign(i + 1) {
+				return true
+			}
+
+			used[j] = false
+		}
+
+		return false
+	}
+
+	if !assign(0) {
+		return nil
+	}
+
+	return assignment
+}
@@ -0,0 +1,144 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+	"github.com/willoma/swaypanion/sway"
+)
+
+const outputLabel = "output"
+
+func displayMessage(display sway.Display) socket.Message {
+	msg := socket.Message{
+		Command: outputLabel,
+		Value:   display.Name,
+		Complement: []string{
+			"Description: " + sway.DisplayDescription(display),
+			"Enabled: " + strconv.FormatBool(display.Active),
+		},
+	}
+
+	if display.Active {
+		msg.Complement = append(
+			msg.Complement,
+			fmt.Sprintf(
+				"Mode: %dx%d@%.3fHz",
+				display.CurrentMode.Width, display.CurrentMode.Height, float64(display.CurrentMode.Refresh),
+			),
+			fmt.Sprintf("Position: %d,%d", display.Rect.X, display.Rect.Y),
+			"Scale: "+strconv.FormatFloat(display.Scale, 'f', -1, 64),
+			"Transform: "+display.Transform,
+		)
+	}
+
+	return msg
+}
+
+func (o *OutputProfiles) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		o.socketList, outputLabel+" list", "List connected outputs",
+		o.socketToggle, outputLabel+" toggle", "Enable or disable an output", "output name or description",
+		o.socketProfile, outputLabel+" profile", "Get current output profile",
+		o.socketProfileList, outputLabel+" profile list", "List output profiles",
+		o.socketProfileApply, outputLabel+" profile apply", "Apply an output profile", "profile name",
+	)
+}
+
+func (o *OutputProfiles) socketList(conn *socketserver.Connection, _ string, _ []string) {
+	displays, err := o.sway.Displays()
+	if err != nil {
+		common.LogError("Failed to get outputs", err)
+		conn.SendError("failed to get outputs")
+
+		return
+	}
+
+	for _, display := range displays {
+		if err := conn.Send(displayMessage(display)); err != nil {
+			common.LogError("Failed to send outputs list", err)
+			return
+		}
+	}
+}
+
+func (o *OutputProfiles) socketToggle(conn *socketserver.Connection, value string, _ []string) {
+	if value == "" {
+		conn.SendError("missing output name")
+		return
+	}
+
+	display, err := o.toggle(value)
+	if err != nil {
+		if errors.Is(err, errUnknownDisplay) {
+			conn.SendError("unknown output")
+			return
+		}
+
+		common.LogError("Failed to toggle output", err)
+		conn.SendError("failed to toggle output")
+
+		return
+	}
+
+	if err := conn.Send(displayMessage(display)); err != nil {
+		common.LogError("Failed to send output", err)
+	}
+}
+
+func (o *OutputProfiles) socketProfile(conn *socketserver.Connection, _ string, _ []string) {
+	name, err := o.currentProfile()
+	if err != nil {
+		conn.SendError("no matching profile")
+		return
+	}
+
+	if err := conn.SendString(outputLabel+" profile", name); err != nil {
+		common.LogError("Failed to send output profile", err)
+	}
+}
+
+func (o *OutputProfiles) socketProfileList(conn *socketserver.Connection, _ string, _ []string) {
+	names := o.profileNames()
+
+	if len(names) == 0 {
+		conn.SendError("no profile configured")
+		return
+	}
+
+	for _, name := range names {
+		if err := conn.SendString(outputLabel+" profile", name); err != nil {
+			common.LogError("Failed to send output profiles list", err)
+			return
+		}
+	}
+}
+
+func (o *OutputProfiles) socketProfileApply(conn *socketserver.Connection, value string, _ []string) {
+	if value == "" {
+		conn.SendError("missing profile name")
+		return
+	}
+
+	if err := o.apply(value); err != nil {
+		switch {
+		case errors.Is(err, errUnknownProfile):
+			conn.SendError("unknown profile")
+		case errors.Is(err, errProfileMismatch):
+			conn.SendError("profile does not match connected outputs")
+		default:
+			common.LogError("Failed to apply output profile", err)
+			conn.SendError("failed to apply output profile")
+		}
+
+		return
+	}
+
+	if err := conn.SendString(outputLabel+" profile", value); err != nil {
+		common.LogError("Failed to send output profile", err)
+	}
+}
@@ -0,0 +1,140 @@
+package modules
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+)
+
+var (
+	testLaptop  = sway.Display{Name: "eDP-1", Make: "BOE", Model: "0x095F", Serial: "Unknown"}
+	testMonitor = sway.Display{Name: "DP-1", Make: "Dell Inc.", Model: "DELL U2720Q", Serial: "ABC123"}
+	testTV      = sway.Display{Name: "HDMI-A-1", Make: "Samsung", Model: "TV", Serial: "XYZ"}
+)
+
+func displayNames(displays []sway.Display) []string {
+	if displays == nil {
+		return nil
+	}
+
+	names := make([]string, len(displays))
+	for i, display := range displays {
+		names[i] = display.Name
+	}
+
+	return names
+}
+
+func TestMatchProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  config.OutputProfile
+		displays []sway.Display
+		want     []string
+	}{
+		{
+			name: "match by name",
+			profile: config.OutputProfile{Outputs: []config.OutputSettings{
+				{Match: "eDP-1"},
+			}},
+			displays: []sway.Display{testLaptop},
+			want:     []string{"eDP-1"},
+		},
+		{
+			name: "match by description",
+			profile: config.OutputProfile{Outputs: []config.OutputSettings{
+				{Match: "eDP-1"},
+				{Match: "Dell Inc. DELL U2720Q ABC123"},
+			}},
+			displays: []sway.Display{testMonitor, testLaptop},
+			want:     []string{"eDP-1", "DP-1"},
+		},
+		{
+			name: "wildcard takes the remaining display",
+			profile: config.OutputProfile{Outputs: []config.OutputSettings{
+				{Match: "*"},
+				{Match: "eDP-1"},
+			}},
+			displays: []sway.Display{testLaptop, testTV},
+			want:     []string{"HDMI-A-1", "eDP-1"},
+		},
+		{
+			name: "missing display",
+			profile: config.OutputProfile{Outputs: []config.OutputSettings{
+				{Match: "eDP-1"},
+				{Match: "DP-1"},
+			}},
+			displays: []sway.Display{testLaptop, testTV},
+			want:     nil,
+		},
+		{
+			name: "more displays than outputs",
+			profile: config.OutputProfile{Outputs: []config.OutputSettings{
+				{Match: "eDP-1"},
+			}},
+			displays: []sway.Display{testLaptop, testMonitor},
+			want:     nil,
+		},
+		{
+			name: "one display for two outputs",
+			profile: config.OutputProfile{Outputs: []config.OutputSettings{
+				{Match: "eDP-1"},
+				{Match: "*"},
+			}},
+			displays: []sway.Display{testLaptop},
+			want:     nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := displayNames(matchProfile(test.profile, test.displays))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("unexpected assignment\n got: %q\nwant: %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDisplaysKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  []sway.Display
+		equal bool
+	}{
+		{
+			name:  "order does not matter",
+			a:     []sway.Display{testLaptop, testMonitor},
+			b:     []sway.Display{testMonitor, testLaptop},
+			equal: true,
+		},
+		{
+			name:  "different displays",
+			a:     []sway.Display{testLaptop, testMonitor},
+			b:     []sway.Display{testLaptop, testTV},
+			equal: false,
+		},
+		{
+			name:  "same connector, other display",
+			a:     []sway.Display{testMonitor},
+			b:     []sway.Display{{Name: "DP-1", Make: "Dell Inc.", Model: "DELL U2720Q", Serial: "DEF456"}},
+			equal: false,
+		},
+		{
+			name:  "additional display",
+			a:     []sway.Display{testLaptop},
+			b:     []sway.Display{testLaptop, testTV},
+			equal: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if equal := displaysKey(test.a) == displaysKey(test.b); equal != test.equal {
+				t.Errorf("unexpected key comparison: got %v, want %v", equal, test.equal)
+			}
+		})
+	}
+}
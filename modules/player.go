@@ -70,6 +70,7 @@ func (p playerData) complement() []string {
 
 type Player struct {
 	sway          *sway.Client
+	apps          *Apps
 	subscriptions *common.Pubsub[playerData]
 
 	stop func()
@@ -77,16 +78,16 @@ type Player struct {
 	mu          sync.Mutex
 	working     bool
 	dbus        *dbus.Conn
-	start       config.Command
-	show        config.Command
+	app         string
 	signalCh    chan *dbus.Signal
 	playerName  string
 	currentData playerData
 }
 
-func NewPlayer(conf *config.Player, swayClient *sway.Client) *Player {
+func NewPlayer(conf *config.Player, swayClient *sway.Client, apps *Apps) *Player {
 	p := &Player{
 		sway:          swayClient,
+		apps:          apps,
 		subscriptions: common.NewPubsub[playerData](),
 	}
 
@@ -137,8 +138,7 @@ func (p *Player) reloadConfig(conf *config.Player) {
 	}
 
 	p.playerName = conf.PlayerName
-	p.start = conf.Start
-	p.show = conf.Show
+	p.app = conf.App
 
 	p.working = true
 
@@ -295,13 +295,12 @@ func (p *Player) next() error {
 	return err
 }
 
-func (p *Player) startOrShow() error {
+// show focuses the player window through its app, which starts the player
+// if needed.
+func (p *Player) show() error {
 	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	if p.currentData.Status == ErrNoPlayer.Error() {
-		return p.start.Run(p.sway)
-	}
+	app := p.app
+	p.mu.Unlock()
 
-	return p.show.Run(p.sway)
+	return p.apps.focus(app)
 }
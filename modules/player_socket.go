@@ -4,7 +4,6 @@ import (
 	"errors"
 
 	"github.com/willoma/swaypanion/common"
-	"github.com/willoma/swaypanion/config"
 	"github.com/willoma/swaypanion/socket"
 	socketserver "github.com/willoma/swaypanion/socket/server"
 )
@@ -86,8 +85,8 @@ func (p *Player) socketUnsubscribe(conn *socketserver.Connection, _ string, _ []
 }
 
 func (p *Player) socketShow(conn *socketserver.Connection, _ string, _ []string) {
-	if err := p.startOrShow(); err != nil {
-		if errors.Is(err, config.ErrNoCommand) {
+	if err := p.show(); err != nil {
+		if errors.Is(err, errUnknownApp) {
 			conn.SendError(err.Error())
 			return
 		}
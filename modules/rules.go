@@ -0,0 +1,184 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+)
+
+// Rules applies actions to windows when they start matching a rule. A rule
+// is applied again only if the window stops matching it then matches it
+// again, so that title changes do not repeat the actions.
+type Rules struct {
+	sway *sway.Client
+	stop func()
+
+	mu      sync.Mutex
+	names   []string
+	rules   map[string]config.Rule
+	matched map[int64]map[string]bool
+}
+
+func NewRules(conf *config.Rules, swayClient *sway.Client) *Rules {
+	r := &Rules{
+		sway:    swayClient,
+		matched: map[int64]map[string]bool{},
+	}
+
+	r.reloadConfig(conf)
+	r.stop = conf.ListenReload(r.reloadConfig)
+
+	r.sway.WindowEvents().Subscribe(r, r.onWindowEvent)
+
+	return r
+}
+
+func (r *Rules) Stop() {
+	r.sway.WindowEvents().Unsubscribe(r)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop != nil {
+		r.stop()
+		r.stop = nil
+	}
+}
+
+func (r *Rules) reloadConfig(conf *config.Rules) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules = maps.Clone(conf.Rules)
+	r.names = make([]string, 0, len(r.rules))
+
+	for name := range r.rules {
+		r.names = append(r.names, name)
+	}
+
+	slices.Sort(r.names)
+}
+
+func (r *Rules) onWindowEvent(event sway.WindowEvent) {
+	window := event.Container
+
+	switch event.Change {
+	case sway.WindowNew, sway.WindowTitle:
+		r.evaluate(&window)
+	case sway.WindowClose:
+		r.mu.Lock()
+		delete(r.matched, window.ID)
+		r.mu.Unlock()
+	}
+}
+
+// evaluate applies the rules, in name order, which the window did not match
+// before.
+func (r *Rules) evaluate(window *sway.Node) {
+	r.mu.Lock()
+	empty := len(r.rules) == 0
+	r.mu.Unlock()
+
+	if empty {
+		return
+	}
+
+	workspaces, err := r.sway.WindowsWorkspaces()
+	if err != nil {
+		if !errors.Is(err, sway.ErrNotConnected) {
+			common.LogError("Failed to get workspaces for window rules", err)
+		}
+
+		return
+	}
+
+	r.mu.Lock()
+
+	matched := r.matched[window.ID]
+	if matched == nil {
+		matched = map[string]bool{}
+		r.matched[window.ID] = matched
+	}
+
+	var toApply []string
+
+	for _, name := range r.names {
+		matches := r.rules[name].Window.MatchWindowInWorkspace(window, workspaces[window.ID])
+
+		if matches && !matched[name] {
+			toApply = append(toApply, name)
+		}
+
+		matched[name] = matches
+	}
+
+	rules := make([]config.Rule, len(toApply))
+	for i, name := range toApply {
+		rules[i] = r.rules[name]
+	}
+
+	r.mu.Unlock()
+
+	for i, rule := range rules {
+		if err := r.apply(window.ID, rule); err != nil {
+			common.LogError("Failed to apply rule "+toApply[i], err)
+		}
+	}
+}
+
+func (r *Rules) apply(id int64, rule config.Rule) error {
+	var errs []error
+
+	for _, action := range rule.Actions {
+		commands := []string{}
+
+		if action.Floating != nil {
+			if *action.Floating {
+				commands = append(commands, "floating enable")
+			} else {
+				commands = append(commands, "floating disable")
+			}
+		}
+
+		if action.Workspace != "" {
+			commands = append(commands, fmt.Sprintf(`move to workspace "%s"`, strings.ReplaceAll(action.Workspace, `"`, `\"`)))
+		}
+
+		if action.Resize != "" {
+			commands = append(commands, "resize set "+action.Resize)
+		}
+
+		if action.Mark != "" {
+			commands = append(commands, fmt.Sprintf(`mark --add "%s"`, strings.ReplaceAll(action.Mark, `"`, `\"`)))
+		}
+
+		if action.Hide {
+			commands = append(commands, "move to scratchpad")
+		}
+
+		if action.Sway != "" {
+			commands = append(commands, action.Sway)
+		}
+
+		if len(commands) > 0 {
+			if err := r.sway.RunCommand(fmt.Sprintf("[con_id=%d] %s", id, strings.Join(commands, ", "))); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		if action.Run.Command != "" {
+			if err := action.Run.Run(r.sway); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
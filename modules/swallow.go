@@ -0,0 +1,418 @@
+package modules
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+)
+
+const swallowMarkPrefix = "_swaypanion_swallow_"
+
+var errInvalidProcStat = errors.New("invalid process stat")
+
+// swallowedTerminal is a terminal hidden by a window, with the place where
+// it must be put back when the window is closed.
+type swallowedTerminal struct {
+	terminal  int64
+	workspace string
+	// anchor is the container next to which the terminal is put back: after
+	// it if it is a window, before it if before is true, inside it if it is
+	// a split container. The terminal is put at the end of the workspace if
+	// anchor is 0.
+	anchor   int64
+	before   bool
+	floating bool
+	// rect is the position and size of a floating terminal, relative to its
+	// workspace
+	rect sway.Rect
+}
+
+func swallowMark(child int64) string {
+	return swallowMarkPrefix + strconv.FormatInt(child, 10)
+}
+
+// Swallow hides the terminal from which a graphical program has been
+// launched, and shows it again when the program window is closed.
+type Swallow struct {
+	sway *sway.Client
+	stop func()
+
+	mu        sync.Mutex
+	enabled   bool
+	terminals []config.WindowIdentification
+	exclude   []config.WindowIdentification
+	swallowed map[int64]swallowedTerminal
+}
+
+func NewSwallow(conf *config.Swallow, swayClient *sway.Client) *Swallow {
+	s := &Swallow{
+		sway:      swayClient,
+		swallowed: map[int64]swallowedTerminal{},
+	}
+
+	s.reloadConfig(conf)
+	s.stop = conf.ListenReload(s.reloadConfig)
+
+	s.sway.WindowEvents().Subscribe(s, s.onWindowEvent)
+
+	return s
+}
+
+func (s *Swallow) Stop() {
+	s.sway.WindowEvents().Unsubscribe(s)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		s.stop()
+		s.stop = nil
+	}
+}
+
+func (s *Swallow) reloadConfig(conf *config.Swallow) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.enabled = conf.Enabled
+	s.terminals = make([]config.WindowIdentification, len(conf.Terminals))
+	copy(s.terminals, conf.Terminals)
+	s.exclude = make([]config.WindowIdentification, len(conf.Exclude))
+	copy(s.exclude, conf.Exclude)
+}
+
+func (s *Swallow) onWindowEvent(event sway.WindowEvent) {
+	window := event.Container
+
+	switch event.Change {
+	case sway.WindowNew:
+		if err := s.swallow(&window); err != nil {
+			common.LogError("Failed to swallow terminal", err)
+		}
+	case sway.WindowClose:
+		if err := s.restore(window.ID); err != nil {
+			common.LogError("Failed to restore swallowed terminal", err)
+		}
+	case sway.WindowMove, sway.WindowFloating:
+	default:
+		return
+	}
+
+	// Windows around the swallowing windows may have changed
+	s.updatePlaces()
+}
+
+func matchAnyWindow(ids []config.WindowIdentification, window *sway.Node, workspace string) bool {
+	return slices.ContainsFunc(ids, func(id config.WindowIdentification) bool {
+		return id.MatchWindowInWorkspace(window, workspace)
+	})
+}
+
+// swallow hides the terminal which is an ancestor process of the new
+// window, and puts the new window at its place. When several terminals share
+// the same process, the terminal on the same workspace as the new window is
+// preferred.
+func (s *Swallow) swallow(window *sway.Node) error {
+	s.mu.Lock()
+	enabled := s.enabled
+	terminals := s.terminals
+	exclude := s.exclude
+	s.mu.Unlock()
+
+	if !enabled || window.PID == nil {
+		return nil
+	}
+
+	root, err := s.sway.Tree()
+	if err != nil {
+		return err
+	}
+
+	positions := sway.WindowPositions(root)
+	workspace := workspaceName(positions[window.ID])
+
+	if matchAnyWindow(terminals, window, workspace) || matchAnyWindow(exclude, window, workspace) {
+		return nil
+	}
+
+	ancestors := processAncestors(int(*window.PID))
+
+	var terminal *sway.Node
+
+	for _, candidate := range sway.Windows(root) {
+		candidateWorkspace := workspaceName(positions[candidate.ID])
+
+		if candidate.ID == window.ID || candidate.PID == nil ||
+			candidateWorkspace == sway.ScratchpadName ||
+			!slices.Contains(ancestors, int(*candidate.PID)) ||
+			!matchAnyWindow(terminals, candidate, candidateWorkspace) {
+			continue
+		}
+
+		if terminal == nil || candidateWorkspace == workspace {
+			terminal = candidate
+		}
+	}
+
+	if terminal == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, swallowed := range s.swallowed {
+		if swallowed.terminal == terminal.ID {
+			// Only the first window launched from a terminal swallows it
+			return nil
+		}
+	}
+
+	mark := swallowMark(window.ID)
+	commands := []string{}
+
+	if !sway.IsFloating(window) && !sway.IsFloating(terminal) {
+		// Marks are unique, marking the window removes the mark from the
+		// terminal
+		commands = append(commands,
+			fmt.Sprintf(`[con_id=%d] mark --add "%s"`, terminal.ID, mark),
+			fmt.Sprintf(`[con_id=%d] move to mark "%s"`, window.ID, mark),
+		)
+	}
+
+	commands = append(commands,
+		fmt.Sprintf(`[con_id=%d] mark --add "%s"`, window.ID, mark),
+		fmt.Sprintf("[con_id=%d] move to scratchpad", terminal.ID),
+		fmt.Sprintf("[con_id=%d] focus", window.ID),
+	)
+
+	if err := s.sway.RunCommand(strings.Join(commands, "; ")); err != nil {
+		return err
+	}
+
+	// The window takes the place of the terminal
+	swallowed := swallowedTerminal{
+		terminal:  terminal.ID,
+		workspace: workspaceName(positions[terminal.ID]),
+		floating:  sway.IsFloating(terminal),
+	}
+
+	swallowed.anchor, swallowed.before, _ = placeAnchor(positions[terminal.ID], window.ID)
+
+	if swallowed.floating {
+		if workspace := positions[terminal.ID].Workspace; workspace != nil {
+			swallowed.rect = sway.Rect{
+				X:      terminal.Rect.X - workspace.Rect.X,
+				Y:      terminal.Rect.Y - workspace.Rect.Y,
+				Width:  terminal.Rect.Width,
+				Height: terminal.Rect.Height,
+			}
+		}
+	}
+
+	s.swallowed[window.ID] = swallowed
+
+	return nil
+}
+
+// updatePlaces records where the windows which swallowed a terminal are, so
+// that the terminals can be put back at their place.
+func (s *Swallow) updatePlaces() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.swallowed) == 0 {
+		return
+	}
+
+	root, err := s.sway.Tree()
+	if err != nil {
+		common.LogError("Failed to get windows for swallowed terminals", err)
+		return
+	}
+
+	positions := sway.WindowPositions(root)
+
+	for child, swallowed := range s.swallowed {
+		position, ok := positions[child]
+		if !ok || workspaceName(position) == sway.ScratchpadName {
+			// Keep the last known place of hidden windows
+			continue
+		}
+
+		swallowed.workspace = workspaceName(position)
+
+		if anchor, before, ok := placeAnchor(position, 0); ok {
+			swallowed.anchor, swallowed.before = anchor, before
+		}
+
+		s.swallowed[child] = swallowed
+	}
+}
+
+func workspaceName(position sway.WindowPosition) string {
+	if position.Workspace == nil {
+		return ""
+	}
+
+	return position.Workspace.Name
+}
+
+// placeAnchor returns the container next to which a window must be moved to
+// take the place of the window at position: a sibling window, or the parent
+// container if there is no sibling window. The exclude window is not
+// considered as a sibling. It returns false if the window is floating.
+func placeAnchor(position sway.WindowPosition, exclude int64) (anchor int64, before, ok bool) {
+	if position.Window == nil || sway.IsFloating(position.Window) {
+		return 0, false, false
+	}
+
+	siblings := slices.DeleteFunc(slices.Clone(position.Parent.Nodes), func(node *sway.Node) bool {
+		return node.ID == exclude
+	})
+
+	i := slices.IndexFunc(siblings, func(node *sway.Node) bool {
+		return node.ID == position.Window.ID
+	})
+
+	switch {
+	case i > 0 && sway.IsWindow(siblings[i-1]):
+		return siblings[i-1].ID, false, true
+	case i != -1 && i < len(siblings)-1 && sway.IsWindow(siblings[i+1]):
+		return siblings[i+1].ID, true, true
+	case len(siblings) > 1 && position.Parent != position.Workspace:
+		return position.Parent.ID, false, true
+	default:
+		return 0, false, true
+	}
+}
+
+// restore shows the terminal swallowed by the closed window, at the place of
+// the closed window. When the terminal itself is closed, it is forgotten.
+func (s *Swallow) restore(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for child, swallowed := range s.swallowed {
+		if swallowed.terminal == id {
+			delete(s.swallowed, child)
+		}
+	}
+
+	swallowed, ok := s.swallowed[id]
+	if !ok {
+		return nil
+	}
+
+	delete(s.swallowed, id)
+
+	if !swallowed.floating && swallowed.anchor != 0 {
+		// The mark of the closed window is free, it is used to find the
+		// anchor. If the anchor is gone, the terminal is put back in the
+		// workspace.
+		mark := swallowMark(id)
+
+		if err := s.sway.RunCommand(fmt.Sprintf(`[con_id=%d] mark --add "%s"`, swallowed.anchor, mark)); err == nil {
+			return s.unsafeRestoreAt(swallowed, mark)
+		}
+	}
+
+	// Tiling a scratchpad window removes it from the scratchpad
+	commands := []string{"scratchpad show", "floating disable"}
+
+	if swallowed.floating {
+		commands = append(commands, "floating enable")
+	}
+
+	commands = append(
+		commands,
+		fmt.Sprintf(`move to workspace "%s"`, strings.ReplaceAll(swallowed.workspace, `"`, `\"`)),
+	)
+
+	if swallowed.floating && swallowed.rect.Width > 0 && swallowed.rect.Height > 0 {
+		commands = append(
+			commands,
+			fmt.Sprintf("resize set %d %d", swallowed.rect.Width, swallowed.rect.Height),
+			fmt.Sprintf("move position %d %d", swallowed.rect.X, swallowed.rect.Y),
+		)
+	}
+
+	commands = append(commands, "focus")
+
+	return s.sway.RunCommand(fmt.Sprintf("[con_id=%d] %s", swallowed.terminal, strings.Join(commands, ", ")))
+}
+
+// unsafeRestoreAt moves the terminal next to its anchor, which holds mark.
+func (s *Swallow) unsafeRestoreAt(swallowed swallowedTerminal, mark string) error {
+	// Tiling a scratchpad window removes it from the scratchpad
+	commands := []string{
+		fmt.Sprintf(`[con_id=%d] scratchpad show, floating disable, move to mark "%s"`, swallowed.terminal, mark),
+	}
+
+	if swallowed.before {
+		commands = append(commands, fmt.Sprintf(
+			"[con_id=%d] swap container with con_id %d", swallowed.terminal, swallowed.anchor,
+		))
+	}
+
+	commands = append(
+		commands,
+		fmt.Sprintf(`unmark "%s"`, mark),
+		fmt.Sprintf("[con_id=%d] focus", swallowed.terminal),
+	)
+
+	return s.sway.RunCommand(strings.Join(commands, "; "))
+}
+
+// processAncestors returns the parent process IDs of the process, from its
+// parent up to init.
+func processAncestors(pid int) []int {
+	var ancestors []int
+
+	for pid > 1 && len(ancestors) < 64 {
+		ppid, err := parentPID(pid)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				common.LogError("Failed to get parent process", err)
+			}
+
+			break
+		}
+
+		ancestors = append(ancestors, ppid)
+		pid = ppid
+	}
+
+	return ancestors
+}
+
+// parentPID reads the parent process ID from /proc/<pid>/stat. The command
+// name, between parentheses, may contain spaces and parentheses, the fields
+// are read after the last closing parenthesis.
+func parentPID(pid int) (int, error) {
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return 0, err
+	}
+
+	end := bytes.LastIndexByte(stat, ')')
+	if end == -1 {
+		return 0, common.Errorf("/proc/"+strconv.Itoa(pid)+"/stat", errInvalidProcStat)
+	}
+
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 2 {
+		return 0, common.Errorf("/proc/"+strconv.Itoa(pid)+"/stat", errInvalidProcStat)
+	}
+
+	return strconv.Atoi(fields[1])
+}
@@ -10,50 +10,146 @@ import (
 )
 
 type SwayNodes struct {
-	sway *sway.Client
-	stop func()
+	sway       *sway.Client
+	hidden     *common.Pubsub[common.Int]
+	workspaces *common.Pubsub[workspacesState]
+	stop       func()
 
 	mu                 sync.Mutex
 	emptyWorkspaceName string
 	hideInsteadOfClose []config.WindowIdentification
+	autoCompact        bool
+	autotile           bool
+	configAutotile     bool
+	autotileWorkspaces []string
+	outputNumbering    config.OutputNumbering
+	outputRangeSize    int
+	autoName           bool
+	appIcons           []config.AppIcon
+	fallbackIcon       string
+	dedupIcons         bool
+	iconSeparator      string
 }
 
 func NewSwayNodes(conf *config.SwayNodes, swayClient *sway.Client) *SwayNodes {
 	s := &SwayNodes{
-		sway: swayClient,
+		sway:       swayClient,
+		hidden:     common.NewPubsub[common.Int](),
+		workspaces: common.NewPubsub[workspacesState](),
 	}
 
 	s.reloadConfig(conf)
 	s.stop = conf.ListenReload(s.reloadConfig)
 
+	s.sway.WindowEvents().Subscribe(&s.hidden, s.onHiddenWindowEvent)
+	s.sway.WorkspaceEvents().Subscribe(&s.workspaces, s.onWorkspacesWorkspaceEvent)
+	s.sway.WindowEvents().Subscribe(&s.workspaces, s.onWorkspacesWindowEvent)
+
 	return s
 }
 
+func (s *SwayNodes) Stop() {
+	s.sway.WindowEvents().Unsubscribe(&s.hidden)
+	s.sway.WorkspaceEvents().Unsubscribe(&s.workspaces)
+	s.sway.WindowEvents().Unsubscribe(&s.workspaces)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		s.stop()
+		s.stop = nil
+	}
+
+	s.unsafeSetAutoCompact(false)
+	s.unsafeSetAutoName(false)
+	s.unsafeSetAutotile(false)
+}
+
 func (s *SwayNodes) reloadConfig(conf *config.SwayNodes) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.emptyWorkspaceName = conf.EmptyWorkspaceName
+	s.outputNumbering = conf.OutputNumbering
+	s.outputRangeSize = conf.OutputRangeSize
 	s.hideInsteadOfClose = make([]config.WindowIdentification, len(conf.HideInsteadOfClose))
 	copy(s.hideInsteadOfClose, conf.HideInsteadOfClose)
+	s.appIcons = make([]config.AppIcon, len(conf.AppIcons))
+	copy(s.appIcons, conf.AppIcons)
+	s.fallbackIcon = conf.FallbackIcon
+	s.dedupIcons = *conf.DedupIcons
+	s.iconSeparator = conf.IconSeparator
+
+	s.unsafeSetAutoCompact(conf.AutoCompact)
+	s.autotileWorkspaces = make([]string, len(conf.AutotileWorkspaces))
+	copy(s.autotileWorkspaces, conf.AutotileWorkspaces)
+
+	s.unsafeSetAutoName(conf.AutoName)
+
+	// Autotiling can be toggled at runtime, the configuration only
+	// overrides it when the configured value changes
+	if conf.Autotile != s.configAutotile {
+		s.configAutotile = conf.Autotile
+		s.unsafeSetAutotile(conf.Autotile)
+	}
+}
+
+func (s *SwayNodes) unsafeSetAutoCompact(enabled bool) {
+	if enabled == s.autoCompact {
+		return
+	}
+
+	s.autoCompact = enabled
+
+	if enabled {
+		s.sway.WorkspaceEvents().Subscribe(&s.autoCompact, s.compactOnWorkspaceEvent)
+	} else {
+		s.sway.WorkspaceEvents().Unsubscribe(&s.autoCompact)
+	}
+}
+
+func (s *SwayNodes) compactOnWorkspaceEvent(event sway.WorkspaceEvent) {
+	switch event.Change {
+	case sway.WorkspaceEmpty, sway.WorkspaceMove:
+		s.compact()
+	}
+}
+
+func (s *SwayNodes) compact() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outputs, err := s.sway.Outputs()
+	if err != nil {
+		common.LogError("Failed to get workspaces", err)
+		return
+	}
+
+	if _, err := s.unsafeRenumber(outputs, -1, -1, true); err != nil {
+		common.LogError("Failed to compact workspaces", err)
+	}
 }
 
 func (s *SwayNodes) moveNext() {
-	rank, workspaces, err := s.sway.CurrentWorkspace()
+	outputRank, rank, outputs, err := s.sway.CurrentOutput()
 	if err != nil {
 		common.LogError("Failed to get current workspace", err)
 		return
 	}
 
+	workspaces := outputs[outputRank].Workspaces
+
 	if rank != len(workspaces)-1 {
 		// Next workspace exists, simply go!
 		if err := s.sway.RunCommand("[con_id=__focused__] move to workspace next_on_output, focus"); err != nil {
 			common.LogError("Failed to move to next workspace", err)
-			return
 		}
+
+		return
 	}
 
-	if nbWindows := sway.CountWindows(workspaces[rank]); nbWindows <= 1 {
+	if nbWindows := len(sway.Windows(workspaces[rank])); nbWindows <= 1 {
 		// Only one window in current workspace, stay here
 		return
 	}
@@ -61,45 +157,36 @@ func (s *SwayNodes) moveNext() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Short path: the workspace has a number
-	if num, _ := sway.ExtractWorkspaceName(workspaces[rank]); num != -1 {
-		if err := s.sway.RunCommand(fmt.Sprintf(
-			`[con_id=__focused__] move to workspace "%s", focus`, sway.MakeWorkspaceName(num+1, s.emptyWorkspaceName),
-		)); err != nil {
-			common.LogError("Failed to move to newly created next workspace", err)
-			return
-		}
-	}
-
-	// Long path: rename all workspaces...
-	if err := s.sway.RenameWorkspacesInOrder(workspaces); err != nil {
-		common.LogError("Failed to rename workspaces in order", err)
+	name, err := s.unsafeNewWorkspace(outputs, outputRank, rank+1)
+	if err != nil {
+		common.LogError("Failed to create next workspace", err)
 		return
 	}
 
-	if err := s.sway.RunCommand(fmt.Sprintf(
-		`[con_id=__focused__] move to workspace "%s", focus`, sway.MakeWorkspaceName(rank+1, s.emptyWorkspaceName),
-	)); err != nil {
+	if err := s.sway.RunCommand(fmt.Sprintf(`[con_id=__focused__] move to workspace "%s", focus`, name)); err != nil {
 		common.LogError("Failed to move to newly created next workspace", err)
 	}
 }
 
 func (s *SwayNodes) movePrev() {
-	rank, workspaces, err := s.sway.CurrentWorkspace()
+	outputRank, rank, outputs, err := s.sway.CurrentOutput()
 	if err != nil {
 		common.LogError("Failed to get current workspace", err)
 		return
 	}
 
+	workspaces := outputs[outputRank].Workspaces
+
 	if rank != 0 {
 		// Previous workspace exists, simply go!
 		if err := s.sway.RunCommand("[con_id=__focused__] move to workspace prev_on_output, focus"); err != nil {
 			common.LogError("Failed to move to previous workspace", err)
-			return
 		}
+
+		return
 	}
 
-	if nbWindows := sway.CountWindows(workspaces[0]); nbWindows <= 1 {
+	if nbWindows := len(sway.Windows(workspaces[rank])); nbWindows <= 1 {
 		// Only one window in current workspace, stay here
 		return
 	}
@@ -107,46 +194,36 @@ func (s *SwayNodes) movePrev() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Short path: the workspace has a number larger than 1
-	if num, _ := sway.ExtractWorkspaceName(workspaces[0]); num > 1 {
-		if err := s.sway.RunCommand(fmt.Sprintf(
-			`[con_id=__focused__] move to workspace "%s", focus`,
-			sway.MakeWorkspaceName(num-1, s.emptyWorkspaceName),
-		)); err != nil {
-			common.LogError("Failed to move to newly created previous workspace", err)
-			return
-		}
-	}
-
-	// Long path: rename all workspaces...
-	if err := s.sway.RenameWorkspacesInOrder(workspaces); err != nil {
-		common.LogError("Failed to rename workspaces in order", err)
+	name, err := s.unsafeNewWorkspace(outputs, outputRank, 0)
+	if err != nil {
+		common.LogError("Failed to create previous workspace", err)
 		return
 	}
 
-	if err := s.sway.RunCommand(fmt.Sprintf(
-		`[con_id=__focused__] move to workspace "%s", focus`, sway.MakeWorkspaceName(1, s.emptyWorkspaceName),
-	)); err != nil {
+	if err := s.sway.RunCommand(fmt.Sprintf(`[con_id=__focused__] move to workspace "%s", focus`, name)); err != nil {
 		common.LogError("Failed to move to newly created previous workspace", err)
 	}
 }
 
 func (s *SwayNodes) next() {
-	rank, workspaces, err := s.sway.CurrentWorkspace()
+	outputRank, rank, outputs, err := s.sway.CurrentOutput()
 	if err != nil {
 		common.LogError("Failed to get current workspace", err)
 		return
 	}
 
+	workspaces := outputs[outputRank].Workspaces
+
 	if rank != len(workspaces)-1 {
 		// Next workspace exists, simply go!
 		if err := s.sway.RunCommand("workspace next_on_output"); err != nil {
 			common.LogError("Failed to go to next workspace", err)
-			return
 		}
+
+		return
 	}
 
-	if nbWindows := sway.CountWindows(workspaces[rank]); nbWindows == 0 {
+	if nbWindows := len(sway.Windows(workspaces[rank])); nbWindows == 0 {
 		// No windows in current workspace, stay here
 		return
 	}
@@ -154,45 +231,36 @@ func (s *SwayNodes) next() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Short path: the workspace has a number
-	if num, _ := sway.ExtractWorkspaceName(workspaces[rank]); num != -1 {
-		if err := s.sway.RunCommand(fmt.Sprintf(
-			`workspace "%s"`, sway.MakeWorkspaceName(num+1, s.emptyWorkspaceName),
-		)); err != nil {
-			common.LogError("Failed to go to newly created next workspace", err)
-			return
-		}
-	}
-
-	// Long path: rename all workspaces...
-	if err := s.sway.RenameWorkspacesInOrder(workspaces); err != nil {
-		common.LogError("Failed to rename workspaces in order", err)
+	name, err := s.unsafeNewWorkspace(outputs, outputRank, rank+1)
+	if err != nil {
+		common.LogError("Failed to create next workspace", err)
 		return
 	}
 
-	if err := s.sway.RunCommand(fmt.Sprintf(
-		`workspace "%s"`, sway.MakeWorkspaceName(rank+1, s.emptyWorkspaceName),
-	)); err != nil {
+	if err := s.sway.RunCommand(fmt.Sprintf(`workspace "%s"`, name)); err != nil {
 		common.LogError("Failed to go to newly created next workspace", err)
 	}
 }
 
 func (s *SwayNodes) prev() {
-	rank, workspaces, err := s.sway.CurrentWorkspace()
+	outputRank, rank, outputs, err := s.sway.CurrentOutput()
 	if err != nil {
 		common.LogError("Failed to get current workspace", err)
 		return
 	}
 
+	workspaces := outputs[outputRank].Workspaces
+
 	if rank != 0 {
 		// Previous workspace exists, simply go!
 		if err := s.sway.RunCommand("workspace prev_on_output"); err != nil {
 			common.LogError("Failed to go to previous workspace", err)
-			return
 		}
+
+		return
 	}
 
-	if nbWindows := sway.CountWindows(workspaces[0]); nbWindows == 0 {
+	if nbWindows := len(sway.Windows(workspaces[rank])); nbWindows == 0 {
 		// No windows in current workspace, stay here
 		return
 	}
@@ -200,25 +268,13 @@ func (s *SwayNodes) prev() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Short path: the workspace has a number larger than 1
-	if num, _ := sway.ExtractWorkspaceName(workspaces[0]); num > 1 {
-		if err := s.sway.RunCommand(fmt.Sprintf(
-			`workspace "%s"`, sway.MakeWorkspaceName(num-1, s.emptyWorkspaceName),
-		)); err != nil {
-			common.LogError("Failed to go to newly created previous workspace", err)
-			return
-		}
-	}
-
-	// Long path: rename all workspaces...
-	if err := s.sway.RenameWorkspacesInOrder(workspaces); err != nil {
-		common.LogError("Failed to rename workspaces in order", err)
+	name, err := s.unsafeNewWorkspace(outputs, outputRank, 0)
+	if err != nil {
+		common.LogError("Failed to create previous workspace", err)
 		return
 	}
 
-	if err := s.sway.RunCommand(fmt.Sprintf(
-		`workspace "%s"`, sway.MakeWorkspaceName(1, s.emptyWorkspaceName),
-	)); err != nil {
+	if err := s.sway.RunCommand(fmt.Sprintf(`workspace "%s"`, name)); err != nil {
 		common.LogError("Failed to go to newly created previous workspace", err)
 	}
 }
@@ -230,14 +286,28 @@ func (s *SwayNodes) hideOrClose() {
 		return
 	}
 
+	if !sway.IsWindow(window) {
+		// An empty workspace is focused, nothing to hide or close
+		return
+	}
+
+	workspaces, err := s.sway.WindowsWorkspaces()
+	if err != nil {
+		common.LogError("Failed to get workspace of currently focused window", err)
+		return
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	for _, id := range s.hideInsteadOfClose {
-		if id.MatchWindow(window) {
+		if id.MatchWindowInWorkspace(window, workspaces[window.ID]) {
 			if err := s.sway.RunCommand("move to scratchpad"); err != nil {
 				common.LogError("Failed to hide window", err)
 			}
+
+			s.publishHidden()
+
 			return
 		}
 	}
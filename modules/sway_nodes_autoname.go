@@ -0,0 +1,94 @@
+package modules
+
+import (
+	"strings"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/sway"
+)
+
+func (s *SwayNodes) unsafeSetAutoName(enabled bool) {
+	if enabled == s.autoName {
+		return
+	}
+
+	s.autoName = enabled
+
+	if enabled {
+		s.sway.WindowEvents().Subscribe(&s.autoName, s.nameOnWindowEvent)
+		go s.name()
+	} else {
+		s.sway.WindowEvents().Unsubscribe(&s.autoName)
+	}
+}
+
+func (s *SwayNodes) nameOnWindowEvent(event sway.WindowEvent) {
+	switch event.Change {
+	case sway.WindowNew, sway.WindowClose, sway.WindowMove:
+		s.name()
+	}
+}
+
+// name renames all numbered workspaces after the icons of the windows they
+// contain, keeping their numbers so that they can still be navigated.
+func (s *SwayNodes) name() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outputs, err := s.sway.Outputs()
+	if err != nil {
+		common.LogError("Failed to get workspaces", err)
+		return
+	}
+
+	for _, output := range outputs {
+		for _, workspace := range output.Workspaces {
+			num, _ := sway.ExtractWorkspaceName(workspace)
+			if num == -1 {
+				continue
+			}
+
+			if err := s.sway.RenameWorkspace(
+				workspace, sway.MakeWorkspaceName(num, s.unsafeWorkspaceIcons(workspace)),
+			); err != nil {
+				common.LogError("Failed to name workspace", err)
+			}
+		}
+	}
+}
+
+func (s *SwayNodes) unsafeWorkspaceIcons(workspace *sway.Node) string {
+	windows := sway.Windows(workspace)
+	if len(windows) == 0 {
+		return s.emptyWorkspaceName
+	}
+
+	icons := make([]string, 0, len(windows))
+	seen := map[string]struct{}{}
+
+	for _, window := range windows {
+		icon := s.unsafeWindowIcon(window, workspace.Name)
+
+		if s.dedupIcons {
+			if _, ok := seen[icon]; ok {
+				continue
+			}
+
+			seen[icon] = struct{}{}
+		}
+
+		icons = append(icons, icon)
+	}
+
+	return strings.Join(icons, s.iconSeparator)
+}
+
+func (s *SwayNodes) unsafeWindowIcon(window *sway.Node, workspace string) string {
+	for _, appIcon := range s.appIcons {
+		if appIcon.Window.MatchWindowInWorkspace(window, workspace) {
+			return appIcon.Icon
+		}
+	}
+
+	return s.fallbackIcon
+}
@@ -0,0 +1,114 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/sway"
+)
+
+func (s *SwayNodes) unsafeSetAutotile(enabled bool) {
+	if enabled == s.autotile {
+		return
+	}
+
+	s.autotile = enabled
+
+	if enabled {
+		s.sway.WindowEvents().Subscribe(&s.autotile, s.autotileOnWindowEvent)
+	} else {
+		s.sway.WindowEvents().Unsubscribe(&s.autotile)
+	}
+}
+
+func (s *SwayNodes) setAutotile(enabled bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unsafeSetAutotile(enabled)
+
+	return s.autotile
+}
+
+func (s *SwayNodes) toggleAutotile() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unsafeSetAutotile(!s.autotile)
+
+	return s.autotile
+}
+
+func (s *SwayNodes) autotileEnabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.autotile
+}
+
+func (s *SwayNodes) autotileOnWindowEvent(event sway.WindowEvent) {
+	switch event.Change {
+	case sway.WindowFocus, sway.WindowMove, sway.WindowFloating, sway.WindowFullscreen:
+		s.tile()
+	}
+}
+
+// tile splits the focused window along its longest side, so that the next
+// window opens next to it in a spiral pattern.
+func (s *SwayNodes) tile() {
+	window, parent, workspace, err := s.sway.FocusedWindow()
+	if err != nil {
+		if !errors.Is(err, sway.ErrCurrentWindowNotFound) && !errors.Is(err, sway.ErrNotConnected) {
+			common.LogError("Failed to get focused window for autotiling", err)
+		}
+
+		return
+	}
+
+	if sway.IsFloating(window) || sway.IsFullscreen(window) || parent == nil ||
+		parent.Layout == sway.LayoutStacked || parent.Layout == sway.LayoutTabbed {
+		return
+	}
+
+	if workspace == nil || !s.autotileWorkspace(workspace) {
+		return
+	}
+
+	newLayout := sway.LayoutSplitH
+	if window.Rect.Height > window.Rect.Width {
+		newLayout = sway.LayoutSplitV
+	}
+
+	if parent.Layout == newLayout {
+		return
+	}
+
+	if err := s.sway.RunCommand(fmt.Sprintf("[con_id=%d] %s", window.ID, newLayout)); err != nil {
+		common.LogError("Failed to autotile focused window", err)
+	}
+}
+
+// autotileWorkspace checks if autotiling is enabled on the workspace. The
+// workspaces may be listed by name or by number, an empty list enables
+// autotiling everywhere.
+func (s *SwayNodes) autotileWorkspace(workspace *sway.Node) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.autotile {
+		return false
+	}
+
+	if len(s.autotileWorkspaces) == 0 {
+		return true
+	}
+
+	num, name := sway.ExtractWorkspaceName(workspace)
+
+	return slices.ContainsFunc(s.autotileWorkspaces, func(entry string) bool {
+		return entry == workspace.Name || entry == name || (num != -1 && entry == strconv.Itoa(num))
+	})
+}
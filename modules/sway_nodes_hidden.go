@@ -0,0 +1,85 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+)
+
+var errNoMatchingWindow = errors.New("no matching window")
+
+func (s *SwayNodes) onHiddenWindowEvent(event sway.WindowEvent) {
+	switch event.Change {
+	case sway.WindowNew, sway.WindowClose, sway.WindowMove, sway.WindowFocus, sway.WindowFloating:
+		s.publishHidden()
+	}
+}
+
+func (s *SwayNodes) publishHidden() {
+	windows, err := s.sway.HiddenWindows()
+	if err != nil {
+		if !errors.Is(err, sway.ErrNotConnected) {
+			common.LogError("Failed to get hidden windows", err)
+		}
+
+		return
+	}
+
+	s.hidden.Publish(common.Int{Value: len(windows)})
+}
+
+// show brings back the first hidden window matching the identification.
+func (s *SwayNodes) show(id config.WindowIdentification) error {
+	windows, err := s.sway.HiddenWindows()
+	if err != nil {
+		return err
+	}
+
+	for _, window := range windows {
+		if id.MatchWindowInWorkspace(window, sway.ScratchpadName) {
+			return s.showWindow(window)
+		}
+	}
+
+	return errNoMatchingWindow
+}
+
+// toggle hides the focused window if it matches the identification,
+// otherwise it shows the first matching hidden window or focuses the first
+// matching visible window.
+func (s *SwayNodes) toggle(id config.WindowIdentification) error {
+	focused, err := s.sway.FocusedNode()
+	if err != nil && !errors.Is(err, sway.ErrCurrentWindowNotFound) {
+		return err
+	}
+
+	windows, workspaces, err := s.sway.WindowsWithWorkspaces()
+	if err != nil {
+		return err
+	}
+
+	if focused != nil && sway.IsWindow(focused) && id.MatchWindowInWorkspace(focused, workspaces[focused.ID]) {
+		defer s.publishHidden()
+		return s.sway.RunCommand(fmt.Sprintf("[con_id=%d] move to scratchpad", focused.ID))
+	}
+
+	if err := s.show(id); !errors.Is(err, errNoMatchingWindow) {
+		return err
+	}
+
+	for _, window := range windows {
+		if id.MatchWindowInWorkspace(window, workspaces[window.ID]) {
+			return s.sway.RunCommand(fmt.Sprintf("[con_id=%d] focus", window.ID))
+		}
+	}
+
+	return errNoMatchingWindow
+}
+
+func (s *SwayNodes) showWindow(window *sway.Node) error {
+	defer s.publishHidden()
+	return s.sway.RunCommand(fmt.Sprintf("[con_id=%d] scratchpad show", window.ID))
+}
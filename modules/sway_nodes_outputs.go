@@ -0,0 +1,220 @@
+package modules
+
+import (
+	"fmt"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+)
+
+// unsafeNewWorkspace returns the name of a new workspace, to be created at
+// rank insertRank of the output. When the neighbour workspaces leave no room
+// for its number, the workspaces are renumbered.
+func (s *SwayNodes) unsafeNewWorkspace(outputs []sway.Output, outputRank, insertRank int) (string, error) {
+	// Short path: a number next to a neighbour workspace is available
+	if num := s.unsafeFreeNumber(outputs, outputRank, insertRank); num != -1 {
+		return sway.MakeWorkspaceName(num, s.emptyWorkspaceName), nil
+	}
+
+	// Long path: renumber workspaces...
+	num, err := s.unsafeRenumber(outputs, outputRank, insertRank, false)
+	if err != nil {
+		return "", err
+	}
+
+	return sway.MakeWorkspaceName(num, s.emptyWorkspaceName), nil
+}
+
+// unsafeFreeNumber returns a number for a new workspace at rank insertRank
+// of the output which keeps the order of workspaces without renumbering
+// them, or -1 if there is none.
+func (s *SwayNodes) unsafeFreeNumber(outputs []sway.Output, outputRank, insertRank int) int {
+	workspaces := outputs[outputRank].Workspaces
+
+	var candidate = -1
+
+	if insertRank > 0 {
+		if num, _ := sway.ExtractWorkspaceName(workspaces[insertRank-1]); num != -1 {
+			candidate = num + 1
+		}
+	} else if insertRank < len(workspaces) {
+		if num, _ := sway.ExtractWorkspaceName(workspaces[insertRank]); num > 1 {
+			candidate = num - 1
+		}
+	}
+
+	if candidate == -1 {
+		return -1
+	}
+
+	minimum, maximum := s.unsafeOutputRange(outputs, outputRank)
+	if candidate < minimum || (maximum != -1 && candidate > maximum) {
+		return -1
+	}
+
+	for _, output := range outputs {
+		for _, workspace := range output.Workspaces {
+			if num, _ := sway.ExtractWorkspaceName(workspace); num == candidate {
+				return -1
+			}
+		}
+	}
+
+	if insertRank < len(workspaces) && insertRank > 0 {
+		if num, _ := sway.ExtractWorkspaceName(workspaces[insertRank]); num != -1 && num <= candidate {
+			return -1
+		}
+	}
+
+	return candidate
+}
+
+// unsafeOutputRange returns the numbers a workspace of the output may have
+// without breaking the numbering scheme, maximum is -1 if there is no limit.
+func (s *SwayNodes) unsafeOutputRange(outputs []sway.Output, outputRank int) (minimum, maximum int) {
+	switch s.outputNumbering {
+	case config.OutputNumberingRanges:
+		return outputRank*s.outputRangeSize + 1, (outputRank + 1) * s.outputRangeSize
+	case config.OutputNumberingGlobal:
+		minimum, maximum = 1, -1
+
+		for i, output := range outputs {
+			for _, workspace := range output.Workspaces {
+				num, _ := sway.ExtractWorkspaceName(workspace)
+				if num == -1 {
+					continue
+				}
+
+				if i < outputRank {
+					minimum = max(minimum, num+1)
+				} else if i > outputRank && (maximum == -1 || num-1 < maximum) {
+					maximum = num - 1
+				}
+			}
+		}
+
+		return minimum, maximum
+	default:
+		return 1, -1
+	}
+}
+
+// unsafeRenumber renumbers the workspaces according to the numbering scheme,
+// making room for a new workspace at rank insertRank of output
+// insertOutput if insertOutput is not -1. It returns the number of the new
+// workspace. When numberedOnly is set, workspaces without a number keep
+// having no number.
+func (s *SwayNodes) unsafeRenumber(outputs []sway.Output, insertOutput, insertRank int, numberedOnly bool) (int, error) {
+	if s.outputNumbering != config.OutputNumberingPerOutput {
+		numbers, inserted := s.unsafeNumbers(outputs, insertOutput, insertRank, numberedOnly)
+		return inserted, s.sway.RenumberWorkspaces(numbers)
+	}
+
+	// Each output is numbered independently
+	var inserted int
+
+	for i := range outputs {
+		if insertOutput != -1 && i != insertOutput {
+			continue
+		}
+
+		numbers, ins := s.unsafeNumbers(outputs[i:i+1], insertOutput-i, insertRank, numberedOnly)
+		if err := s.sway.RenumberWorkspaces(numbers); err != nil {
+			return 0, err
+		}
+
+		if i == insertOutput {
+			inserted = ins
+		}
+	}
+
+	return inserted, nil
+}
+
+func (s *SwayNodes) unsafeNumbers(
+	outputs []sway.Output, insertOutput, insertRank int, numberedOnly bool,
+) (numbers map[*sway.Node]int, inserted int) {
+	numbers = map[*sway.Node]int{}
+
+	var last int
+
+	for i, output := range outputs {
+		switch s.outputNumbering {
+		case config.OutputNumberingRanges:
+			// If an output has too many workspaces, its numbers overflow
+			// on the range of the next output
+			last = max(last, i*s.outputRangeSize)
+		case config.OutputNumberingPerOutput:
+			last = 0
+		}
+
+		for j := 0; j <= len(output.Workspaces); j++ {
+			if i == insertOutput && j == insertRank {
+				last++
+				inserted = last
+			}
+
+			if j == len(output.Workspaces) {
+				break
+			}
+
+			workspace := output.Workspaces[j]
+
+			if num, _ := sway.ExtractWorkspaceName(workspace); numberedOnly && num == -1 {
+				continue
+			}
+
+			last++
+			numbers[workspace] = last
+		}
+	}
+
+	return numbers, inserted
+}
+
+// moveToOutput moves the focused workspace to the next or previous output,
+// then renumbers the workspaces so that each output has contiguous numbers.
+func (s *SwayNodes) moveToOutput(offset int) {
+	outputRank, _, outputs, err := s.sway.CurrentOutput()
+	if err != nil {
+		common.LogError("Failed to get current workspace", err)
+		return
+	}
+
+	if len(outputs) < 2 {
+		return
+	}
+
+	target := outputs[(outputRank+offset+len(outputs))%len(outputs)]
+
+	if err := s.sway.RunCommand(fmt.Sprintf(`move workspace to output "%s"`, target.Name)); err != nil {
+		common.LogError("Failed to move workspace to output", err)
+		return
+	}
+
+	// Keep each output's workspace numbers contiguous
+	s.compact()
+}
+
+// moveWindowToOutput moves the focused window to the visible workspace of
+// the next or previous output.
+func (s *SwayNodes) moveWindowToOutput(offset int) {
+	outputRank, _, outputs, err := s.sway.CurrentOutput()
+	if err != nil {
+		common.LogError("Failed to get current workspace", err)
+		return
+	}
+
+	if len(outputs) < 2 {
+		return
+	}
+
+	target := outputs[(outputRank+offset+len(outputs))%len(outputs)]
+
+	if err := s.sway.RunCommand(fmt.Sprintf(
+		`[con_id=__focused__] move to output "%s", focus`, target.Name,
+	)); err != nil {
+		common.LogError("Failed to move window to output", err)
+	}
+}
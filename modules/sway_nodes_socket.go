@@ -1,10 +1,23 @@
 package modules
 
-import socketserver "github.com/willoma/swaypanion/socket/server"
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+	"github.com/willoma/swaypanion/sway"
+)
 
 const (
 	dynworkspaceLabel = "dynworkspace"
 	windowLabel       = "window"
+	autotileLabel     = "autotile"
+	workspacesLabel   = "workspaces"
 )
 
 func (s *SwayNodes) SocketCommands() socketserver.Commands {
@@ -13,7 +26,24 @@ func (s *SwayNodes) SocketCommands() socketserver.Commands {
 		s.socketPrev, dynworkspaceLabel+" previous", "Go to previous workspace, creating it if needed",
 		s.socketMoveNext, dynworkspaceLabel+" move next", "Move focused window to next workspace, creating it if needed",
 		s.socketMovePrev, dynworkspaceLabel+" move previous", "Move focused window to previous workspace, creating it if needed",
+		s.socketOutputNext, dynworkspaceLabel+" output next", "Move focused workspace to next output",
+		s.socketOutputPrev, dynworkspaceLabel+" output previous", "Move focused workspace to previous output",
+		s.socketMoveOutputNext, dynworkspaceLabel+" move output next", "Move focused window to next output",
+		s.socketMoveOutputPrev, dynworkspaceLabel+" move output previous", "Move focused window to previous output",
+		s.socketCompact, dynworkspaceLabel+" compact", "Renumber workspaces to remove gaps in their numbers",
 		s.socketHideOrClose, windowLabel+" hide-or-close", "Hide or close the focused window, depending on the Swaypanion configuration",
+		s.socketAutotile, autotileLabel, "Get autotiling state",
+		s.socketAutotileOn, autotileLabel+" on", "Enable autotiling",
+		s.socketAutotileOff, autotileLabel+" off", "Disable autotiling",
+		s.socketAutotileToggle, autotileLabel+" toggle", "Toggle autotiling",
+		s.socketHiddenList, windowLabel+" hidden list", "List hidden windows",
+		s.socketHiddenSubscribe, windowLabel+" hidden subscribe", "Get the number of hidden windows each time it changes",
+		s.socketHiddenUnsubscribe, windowLabel+" hidden unsubscribe", "Stop getting the number of hidden windows on change",
+		s.socketShow, windowLabel+" show", "Show a hidden window", "window identification (<type>=<match>, <type>~=<match> or <type>=/<regex>/)",
+		s.socketToggle, windowLabel+" toggle", "Show a hidden window, or hide it if it is focused", "window identification (<type>=<match>, <type>~=<match> or <type>=/<regex>/)",
+		s.socketWorkspaces, workspacesLabel, "Get workspaces of each output, as JSON",
+		s.socketWorkspacesSubscribe, workspacesLabel+" subscribe", "Get workspaces of each output, as JSON, each time they change",
+		s.socketWorkspacesUnsubscribe, workspacesLabel+" unsubscribe", "Stop getting workspaces on change",
 	)
 }
 
@@ -33,6 +63,197 @@ func (s *SwayNodes) socketMovePrev(*socketserver.Connection, string, []string) {
 	s.movePrev()
 }
 
+func (s *SwayNodes) socketOutputNext(*socketserver.Connection, string, []string) {
+	s.moveToOutput(1)
+}
+
+func (s *SwayNodes) socketOutputPrev(*socketserver.Connection, string, []string) {
+	s.moveToOutput(-1)
+}
+
+func (s *SwayNodes) socketMoveOutputNext(*socketserver.Connection, string, []string) {
+	s.moveWindowToOutput(1)
+}
+
+func (s *SwayNodes) socketMoveOutputPrev(*socketserver.Connection, string, []string) {
+	s.moveWindowToOutput(-1)
+}
+
+func (s *SwayNodes) socketCompact(*socketserver.Connection, string, []string) {
+	s.compact()
+}
+
 func (s *SwayNodes) socketHideOrClose(*socketserver.Connection, string, []string) {
 	s.hideOrClose()
 }
+
+func hiddenWindowMessage(window *sway.Node) socket.Message {
+	return socket.Message{
+		Command: windowLabel,
+		Value:   strconv.FormatInt(window.ID, 10),
+		Complement: []string{
+			"App: " + sway.AppName(window),
+			"Title: " + window.Name,
+		},
+	}
+}
+
+func (s *SwayNodes) socketHiddenList(conn *socketserver.Connection, _ string, _ []string) {
+	windows, err := s.sway.HiddenWindows()
+	if err != nil {
+		common.LogError("Failed to get hidden windows", err)
+		conn.SendError("failed to get hidden windows")
+
+		return
+	}
+
+	if len(windows) == 0 {
+		if err := conn.SendString(windowLabel, "no hidden window"); err != nil {
+			common.LogError("Failed to send hidden windows", err)
+		}
+
+		return
+	}
+
+	for _, window := range windows {
+		if err := conn.Send(hiddenWindowMessage(window)); err != nil {
+			common.LogError("Failed to send hidden windows", err)
+			return
+		}
+	}
+}
+
+func (s *SwayNodes) socketHiddenSubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	s.publishHidden()
+
+	s.hidden.Subscribe(conn, true, func(value common.Int) {
+		if err := conn.SendInt(windowLabel+" hidden", value.Value); err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				s.hidden.Unsubscribe(conn)
+				return
+			}
+
+			common.LogError("Failed to send subscribed hidden windows", err)
+		}
+	})
+}
+
+func (s *SwayNodes) socketHiddenUnsubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	s.hidden.Unsubscribe(conn)
+}
+
+func (s *SwayNodes) socketShow(conn *socketserver.Connection, value string, _ []string) {
+	id, err := config.ParseWindowIdentification(value)
+	if err != nil {
+		conn.SendError(err.Error())
+		return
+	}
+
+	if err := s.show(id); err != nil {
+		if !errors.Is(err, errNoMatchingWindow) {
+			common.LogError("Failed to show window", err)
+		}
+
+		conn.SendError("failed to show window: " + err.Error())
+	}
+}
+
+func (s *SwayNodes) socketToggle(conn *socketserver.Connection, value string, _ []string) {
+	id, err := config.ParseWindowIdentification(value)
+	if err != nil {
+		conn.SendError(err.Error())
+		return
+	}
+
+	if err := s.toggle(id); err != nil {
+		if !errors.Is(err, errNoMatchingWindow) {
+			common.LogError("Failed to toggle window", err)
+		}
+
+		conn.SendError("failed to toggle window: " + err.Error())
+	}
+}
+
+func (s *SwayNodes) sendAutotile(conn *socketserver.Connection, enabled bool) {
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+
+	if err := conn.SendString(autotileLabel, state); err != nil {
+		common.LogError("Failed to send autotiling state", err)
+	}
+}
+
+func (s *SwayNodes) socketAutotile(conn *socketserver.Connection, _ string, _ []string) {
+	s.sendAutotile(conn, s.autotileEnabled())
+}
+
+func (s *SwayNodes) socketAutotileOn(conn *socketserver.Connection, _ string, _ []string) {
+	s.sendAutotile(conn, s.setAutotile(true))
+}
+
+func (s *SwayNodes) socketAutotileOff(conn *socketserver.Connection, _ string, _ []string) {
+	s.sendAutotile(conn, s.setAutotile(false))
+}
+
+func (s *SwayNodes) socketAutotileToggle(conn *socketserver.Connection, _ string, _ []string) {
+	s.sendAutotile(conn, s.toggleAutotile())
+}
+
+func (w workspacesState) message() (socket.Message, error) {
+	value, err := json.Marshal(w)
+	if err != nil {
+		return socket.Message{}, err
+	}
+
+	return socket.Message{
+		Command: workspacesLabel,
+		Value:   string(value),
+	}, nil
+}
+
+func (s *SwayNodes) socketWorkspaces(conn *socketserver.Connection, _ string, _ []string) {
+	state, err := s.publishWorkspaces()
+	if err != nil {
+		conn.SendError("failed to get workspaces")
+		return
+	}
+
+	msg, err := state.message()
+	if err != nil {
+		common.LogError("Failed to encode workspaces", err)
+		conn.SendError("failed to encode workspaces")
+
+		return
+	}
+
+	if err := conn.Send(msg); err != nil {
+		common.LogError("Failed to send workspaces", err)
+	}
+}
+
+func (s *SwayNodes) socketWorkspacesSubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	s.publishWorkspaces()
+
+	s.workspaces.Subscribe(conn, true, func(value workspacesState) {
+		msg, err := value.message()
+		if err != nil {
+			common.LogError("Failed to encode subscribed workspaces", err)
+			return
+		}
+
+		if err := conn.Send(msg); err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				s.workspaces.Unsubscribe(conn)
+				return
+			}
+
+			common.LogError("Failed to send subscribed workspaces", err)
+		}
+	})
+}
+
+func (s *SwayNodes) socketWorkspacesUnsubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	s.workspaces.Unsubscribe(conn)
+}
@@ -0,0 +1,280 @@
+package modules
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	gosway "github.com/joshuarubin/go-sway"
+
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/sway"
+	"github.com/willoma/swaypanion/sway/swaytest"
+)
+
+// testSwayNodes is the configuration used by the tests, a copy of the
+// defaults which the tests do not share with the rest of the program.
+var testSwayNodes = func() *config.SwayNodes {
+	d := config.DefaultSwayNodes
+	dedupIcons := *d.DedupIcons
+
+	return &config.SwayNodes{
+		EmptyWorkspaceName: d.EmptyWorkspaceName,
+		AutoCompact:        d.AutoCompact,
+		OutputNumbering:    d.OutputNumbering,
+		OutputRangeSize:    d.OutputRangeSize,
+		AutoName:           d.AutoName,
+		Autotile:           d.Autotile,
+		AutotileWorkspaces: slices.Clone(d.AutotileWorkspaces),
+		AppIcons:           slices.Clone(d.AppIcons),
+		FallbackIcon:       d.FallbackIcon,
+		DedupIcons:         &dedupIcons,
+		IconSeparator:      d.IconSeparator,
+		HideInsteadOfClose: slices.Clone(d.HideInsteadOfClose),
+	}
+}()
+
+// placeholder returns the name of the placeholder workspace with the number.
+func placeholder(num int) string {
+	return sway.MakeWorkspaceName(num, testSwayNodes.EmptyWorkspaceName)
+}
+
+type swayNodesTest struct {
+	name     string
+	tree     *gosway.Node
+	commands []string
+}
+
+func runSwayNodesTests(t *testing.T, tests []swayNodesTest, action func(*SwayNodes)) {
+	t.Helper()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := swaytest.NewServer(t)
+			server.SetTree(test.tree)
+
+			s := NewSwayNodes(testSwayNodes, server.Client())
+			defer s.Stop()
+
+			action(s)
+
+			if commands := server.Commands(); !slices.Equal(commands, test.commands) {
+				t.Errorf("unexpected sway commands\n got: %q\nwant: %q", commands, test.commands)
+			}
+		})
+	}
+}
+
+func TestSwayNodesNext(t *testing.T) {
+	runSwayNodesTests(t, []swayNodesTest{
+		{
+			name: "next workspace exists",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Focused(swaytest.Window("foot", "term"))),
+				swaytest.Workspace("2: b", swaytest.Window("firefox", "web")),
+			)),
+			commands: []string{"workspace next_on_output"},
+		},
+		{
+			name: "last workspace is empty",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Window("foot", "term")),
+				swaytest.Focused(swaytest.Workspace(placeholder(2))),
+			)),
+			commands: nil,
+		},
+		{
+			name: "last workspace has a number",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Window("foot", "term")),
+				swaytest.Workspace("5: b", swaytest.Focused(swaytest.Window("firefox", "web"))),
+			)),
+			commands: []string{fmt.Sprintf(`workspace "%s"`, placeholder(6))},
+		},
+		{
+			name: "unnumbered workspace",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("web", swaytest.Focused(swaytest.Window("firefox", "web"))),
+			)),
+			commands: []string{
+				`rename workspace "web" to "1: web"`,
+				fmt.Sprintf(`workspace "%s"`, placeholder(2)),
+			},
+		},
+	}, (*SwayNodes).next)
+}
+
+func TestSwayNodesPrev(t *testing.T) {
+	runSwayNodesTests(t, []swayNodesTest{
+		{
+			name: "previous workspace exists",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Window("foot", "term")),
+				swaytest.Workspace("2: b", swaytest.Focused(swaytest.Window("firefox", "web"))),
+			)),
+			commands: []string{"workspace prev_on_output"},
+		},
+		{
+			name: "first workspace is empty",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Focused(swaytest.Workspace(placeholder(1))),
+				swaytest.Workspace("2: b", swaytest.Window("firefox", "web")),
+			)),
+			commands: nil,
+		},
+		{
+			name: "first workspace has number 1",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Focused(swaytest.Window("foot", "term"))),
+				swaytest.Workspace("2: b", swaytest.Window("firefox", "web")),
+			)),
+			commands: []string{
+				`rename workspace "2: b" to "3: b"`,
+				`rename workspace "1: a" to "2: a"`,
+				fmt.Sprintf(`workspace "%s"`, placeholder(1)),
+			},
+		},
+		{
+			name: "first workspace has a larger number",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("3: a", swaytest.Focused(swaytest.Window("foot", "term"))),
+			)),
+			commands: []string{fmt.Sprintf(`workspace "%s"`, placeholder(2))},
+		},
+		{
+			name: "unnumbered first workspace",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("web", swaytest.Focused(swaytest.Window("firefox", "web"))),
+				swaytest.Workspace("2: b", swaytest.Window("foot", "term")),
+			)),
+			commands: []string{
+				`rename workspace "2: b" to "3: b"`,
+				`rename workspace "web" to "2: web"`,
+				fmt.Sprintf(`workspace "%s"`, placeholder(1)),
+			},
+		},
+	}, (*SwayNodes).prev)
+}
+
+func TestSwayNodesMoveNext(t *testing.T) {
+	runSwayNodesTests(t, []swayNodesTest{
+		{
+			name: "next workspace exists",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Focused(swaytest.Window("foot", "term"))),
+				swaytest.Workspace("2: b", swaytest.Window("firefox", "web")),
+			)),
+			commands: []string{"[con_id=__focused__] move to workspace next_on_output, focus"},
+		},
+		{
+			name: "single window in last workspace",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Focused(swaytest.Window("foot", "term"))),
+			)),
+			commands: nil,
+		},
+		{
+			name: "several windows in last workspace",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a",
+					swaytest.Focused(swaytest.Window("foot", "term")),
+					swaytest.Window("firefox", "web"),
+				),
+			)),
+			commands: []string{fmt.Sprintf(`[con_id=__focused__] move to workspace "%s", focus`, placeholder(2))},
+		},
+		{
+			name: "several windows in unnumbered last workspace",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("web",
+					swaytest.Focused(swaytest.Window("foot", "term")),
+					swaytest.Window("firefox", "web"),
+				),
+			)),
+			commands: []string{
+				`rename workspace "web" to "1: web"`,
+				fmt.Sprintf(`[con_id=__focused__] move to workspace "%s", focus`, placeholder(2)),
+			},
+		},
+	}, (*SwayNodes).moveNext)
+}
+
+func TestSwayNodesMovePrev(t *testing.T) {
+	runSwayNodesTests(t, []swayNodesTest{
+		{
+			name: "previous workspace exists",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Window("foot", "term")),
+				swaytest.Workspace("2: b", swaytest.Focused(swaytest.Window("firefox", "web"))),
+			)),
+			commands: []string{"[con_id=__focused__] move to workspace prev_on_output, focus"},
+		},
+		{
+			name: "single window in first workspace",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Focused(swaytest.Window("foot", "term"))),
+				swaytest.Workspace("2: b", swaytest.Window("firefox", "web")),
+			)),
+			commands: nil,
+		},
+		{
+			name: "several windows in first workspace",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a",
+					swaytest.Focused(swaytest.Window("foot", "term")),
+					swaytest.Window("firefox", "web"),
+				),
+			)),
+			commands: []string{
+				`rename workspace "1: a" to "2: a"`,
+				fmt.Sprintf(`[con_id=__focused__] move to workspace "%s", focus`, placeholder(1)),
+			},
+		},
+		{
+			name: "several windows in tiled containers",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("4: a",
+					swaytest.Container(gosway.LayoutSplitV,
+						swaytest.Focused(swaytest.Window("foot", "term")),
+					),
+					swaytest.Window("firefox", "web"),
+				),
+			)),
+			commands: []string{fmt.Sprintf(`[con_id=__focused__] move to workspace "%s", focus`, placeholder(3))},
+		},
+	}, (*SwayNodes).movePrev)
+}
+
+func TestSwayNodesHideOrClose(t *testing.T) {
+	runSwayNodesTests(t, []swayNodesTest{
+		{
+			name: "window to hide",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Focused(swaytest.XWindow("Spotify", "spotify", "Spotify"))),
+			)),
+			commands: []string{"move to scratchpad"},
+		},
+		{
+			name: "window to close",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Focused(swaytest.Window("foot", "term"))),
+			)),
+			commands: []string{"kill"},
+		},
+		{
+			name: "empty workspace focused",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Window("foot", "term")),
+				swaytest.Focused(swaytest.Workspace(placeholder(2))),
+			)),
+			commands: nil,
+		},
+		{
+			name: "no focused window",
+			tree: swaytest.Root(swaytest.Output("eDP-1",
+				swaytest.Workspace("1: a", swaytest.Window("foot", "term")),
+			)),
+			commands: nil,
+		},
+	}, (*SwayNodes).hideOrClose)
+}
@@ -0,0 +1,96 @@
+package modules
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/sway"
+)
+
+type workspaceState struct {
+	Name    string `json:"name"`
+	Number  int    `json:"number"`
+	Label   string `json:"label"`
+	Focused bool   `json:"focused"`
+	Visible bool   `json:"visible"`
+	Urgent  bool   `json:"urgent"`
+	Windows int    `json:"windows"`
+	Icons   string `json:"icons"`
+	// Placeholder is an empty workspace created by the dynamic workspaces,
+	// named after EmptyWorkspaceName
+	Placeholder bool `json:"placeholder"`
+}
+
+type outputWorkspaces struct {
+	Output     string           `json:"output"`
+	Workspaces []workspaceState `json:"workspaces"`
+}
+
+type workspacesState []outputWorkspaces
+
+func (w workspacesState) Equal(other workspacesState) bool {
+	return slices.EqualFunc(w, other, func(a, b outputWorkspaces) bool {
+		return a.Output == b.Output && slices.Equal(a.Workspaces, b.Workspaces)
+	})
+}
+
+func (s *SwayNodes) onWorkspacesWorkspaceEvent(sway.WorkspaceEvent) {
+	s.publishWorkspaces()
+}
+
+func (s *SwayNodes) onWorkspacesWindowEvent(event sway.WindowEvent) {
+	switch event.Change {
+	case sway.WindowNew, sway.WindowClose, sway.WindowMove, sway.WindowUrgent:
+		s.publishWorkspaces()
+	}
+}
+
+func (s *SwayNodes) publishWorkspaces() (workspacesState, error) {
+	outputs, err := s.sway.Outputs()
+	if err != nil {
+		if !errors.Is(err, sway.ErrNotConnected) {
+			common.LogError("Failed to get workspaces", err)
+		}
+
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := make(workspacesState, len(outputs))
+
+	for i, output := range outputs {
+		state[i] = outputWorkspaces{
+			Output:     output.Name,
+			Workspaces: make([]workspaceState, len(output.Workspaces)),
+		}
+
+		for j, workspace := range output.Workspaces {
+			num, label := sway.ExtractWorkspaceName(workspace)
+			windows := len(sway.Windows(workspace))
+
+			ws := workspaceState{
+				Name:        workspace.Name,
+				Number:      num,
+				Label:       label,
+				Focused:     sway.HasFocus(workspace),
+				Visible:     workspace.ID == output.Visible,
+				Urgent:      workspace.Urgent != nil && *workspace.Urgent,
+				Windows:     windows,
+				Placeholder: windows == 0 && label == s.emptyWorkspaceName,
+			}
+
+			if windows > 0 {
+				ws.Icons = s.unsafeWorkspaceIcons(workspace)
+			}
+
+			state[i].Workspaces[j] = ws
+		}
+	}
+
+	s.workspaces.Publish(state)
+
+	return state, nil
+}
@@ -0,0 +1,218 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/notification"
+	"github.com/willoma/swaypanion/sway"
+)
+
+var errNoUrgentWindow = errors.New("no urgent window")
+
+type urgentWindows []focusEntry
+
+func (u urgentWindows) Equal(other urgentWindows) bool {
+	return slices.Equal(u, other)
+}
+
+// Urgent keeps the urgent windows, the oldest first.
+type Urgent struct {
+	sway          *sway.Client
+	notifier      *notification.MessageNotifier
+	subscriptions *common.Pubsub[urgentWindows]
+
+	stop func()
+
+	mu      sync.Mutex
+	entries urgentWindows
+}
+
+func NewUrgent(conf *config.Urgent, swayClient *sway.Client, notif *notification.Notification) *Urgent {
+	u := &Urgent{
+		sway:          swayClient,
+		notifier:      notif.MessageNotifier(),
+		subscriptions: common.NewPubsub[urgentWindows](),
+	}
+
+	u.reloadConfig(conf)
+	u.stop = conf.ListenReload(u.reloadConfig)
+
+	u.sway.WindowEvents().Subscribe(u, u.onWindowEvent)
+
+	u.sway.OnConnected(u.init)
+
+	return u
+}
+
+func (u *Urgent) Stop() {
+	u.sway.WindowEvents().Unsubscribe(u)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.stop != nil {
+		u.stop()
+		u.stop = nil
+	}
+}
+
+func (u *Urgent) reloadConfig(conf *config.Urgent) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.notifier.Reconfigure(conf.Notification)
+}
+
+// init adds the windows which were already urgent before swaypanion was
+// started.
+func (u *Urgent) init() {
+	windows, err := u.sway.Windows()
+	if err != nil {
+		common.LogError("Failed to get urgent windows", err)
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, window := range windows {
+		if window.Urgent != nil && *window.Urgent && u.unsafeIndex(window.ID) == -1 {
+			u.entries = append(u.entries, focusEntry{
+				id:    window.ID,
+				appID: sway.AppName(window),
+				title: window.Name,
+			})
+		}
+	}
+
+	u.unsafePublish()
+}
+
+func (u *Urgent) onWindowEvent(event sway.WindowEvent) {
+	window := event.Container
+
+	switch event.Change {
+	case sway.WindowUrgent:
+		if window.Urgent == nil || !*window.Urgent {
+			u.remove(window.ID)
+			return
+		}
+
+		entry := focusEntry{
+			id:    window.ID,
+			appID: sway.AppName(&window),
+			title: window.Name,
+		}
+
+		u.mu.Lock()
+
+		if u.unsafeIndex(window.ID) != -1 {
+			u.mu.Unlock()
+			return
+		}
+
+		u.entries = append(u.entries, entry)
+		u.unsafePublish()
+
+		u.mu.Unlock()
+
+		u.notify(entry)
+	case sway.WindowClose:
+		u.remove(window.ID)
+	case sway.WindowTitle:
+		u.mu.Lock()
+		defer u.mu.Unlock()
+
+		if i := u.unsafeIndex(window.ID); i != -1 {
+			u.entries[i].title = window.Name
+			u.unsafePublish()
+		}
+	}
+}
+
+// notify sends a notification if the window is on a workspace which is not
+// visible on any output.
+func (u *Urgent) notify(entry focusEntry) {
+	outputs, err := u.sway.Outputs()
+	if err != nil {
+		common.LogError("Failed to get workspace of urgent window", err)
+		return
+	}
+
+	for _, output := range outputs {
+		for _, workspace := range output.Workspaces {
+			if !slices.ContainsFunc(sway.Windows(workspace), func(window *sway.Node) bool {
+				return window.ID == entry.id
+			}) {
+				continue
+			}
+
+			if workspace.ID != output.Visible {
+				u.notifier.Notify(fmt.Sprintf("%s needs attention on workspace %s", entry.appID, workspace.Name))
+			}
+
+			return
+		}
+	}
+}
+
+func (u *Urgent) remove(id int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if i := u.unsafeIndex(id); i != -1 {
+		u.entries = slices.Delete(u.entries, i, i+1)
+		u.unsafePublish()
+	}
+}
+
+func (u *Urgent) unsafeIndex(id int64) int {
+	return slices.IndexFunc(u.entries, func(e focusEntry) bool {
+		return e.id == id
+	})
+}
+
+func (u *Urgent) unsafePublish() {
+	u.subscriptions.Publish(slices.Clone(u.entries))
+}
+
+// list returns the urgent windows, with the names of their current
+// workspaces.
+func (u *Urgent) list() (urgentWindows, error) {
+	workspaces, err := u.sway.WindowsWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	entries := slices.Clone(u.entries)
+	for i := range entries {
+		entries[i].workspace = workspaces[entries[i].id]
+	}
+
+	return entries, nil
+}
+
+// focus jumps to the oldest urgent window. Sway removes the urgency when the
+// window gets the focus.
+func (u *Urgent) focus() error {
+	u.mu.Lock()
+
+	if len(u.entries) == 0 {
+		u.mu.Unlock()
+		return errNoUrgentWindow
+	}
+
+	id := u.entries[0].id
+
+	u.mu.Unlock()
+
+	return u.sway.RunCommand(fmt.Sprintf("[con_id=%d] focus", id))
+}
@@ -0,0 +1,88 @@
+package modules
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+)
+
+func (u urgentWindows) message() socket.Message {
+	apps := make([]string, len(u))
+	for i, entry := range u {
+		apps[i] = entry.appID
+	}
+
+	return socket.Message{
+		Command:    windowLabel + " urgent",
+		Value:      strconv.Itoa(len(u)),
+		Complement: []string{"Apps: " + strings.Join(apps, ", ")},
+	}
+}
+
+func (u *Urgent) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		u.socketList, windowLabel+" urgent list", "List urgent windows, oldest first",
+		u.socketFocus, windowLabel+" urgent focus", "Focus the oldest urgent window",
+		u.socketSubscribe, windowLabel+" urgent subscribe", "Get urgent windows each time they change",
+		u.socketUnsubscribe, windowLabel+" urgent unsubscribe", "Stop getting urgent windows on change",
+	)
+}
+
+func (u *Urgent) socketList(conn *socketserver.Connection, _ string, _ []string) {
+	entries, err := u.list()
+	if err != nil {
+		common.LogError("Failed to get workspaces", err)
+		conn.SendError("failed to get workspaces")
+
+		return
+	}
+
+	if len(entries) == 0 {
+		if err := conn.SendString(windowLabel, "no urgent window"); err != nil {
+			common.LogError("Failed to send urgent windows list", err)
+		}
+
+		return
+	}
+
+	for _, entry := range entries {
+		if err := conn.Send(entry.message()); err != nil {
+			common.LogError("Failed to send urgent windows list", err)
+			return
+		}
+	}
+}
+
+func (u *Urgent) socketFocus(conn *socketserver.Connection, _ string, _ []string) {
+	if err := u.focus(); err != nil {
+		if errors.Is(err, errNoUrgentWindow) {
+			conn.SendError("no urgent window")
+			return
+		}
+
+		common.LogError("Failed to focus urgent window", err)
+		conn.SendError("failed to focus urgent window")
+	}
+}
+
+func (u *Urgent) socketSubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	u.subscriptions.Subscribe(conn, true, func(value urgentWindows) {
+		if err := conn.Send(value.message()); err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				u.subscriptions.Unsubscribe(conn)
+				return
+			}
+
+			common.LogError("Failed to send subscribed urgent windows", err)
+		}
+	})
+}
+
+func (u *Urgent) socketUnsubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	u.subscriptions.Unsubscribe(conn)
+}
@@ -53,6 +53,7 @@ func (v *Volume) Stop() {
 	}
 
 	v.subscriptions.Unsubscribe(v.notifier)
+	v.notifier.Close()
 }
 
 func (v *Volume) reloadConfig(conf *config.Volume) {
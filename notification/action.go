@@ -0,0 +1,82 @@
+package notification
+
+import (
+	"strconv"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+	"github.com/willoma/swaypanion/sway"
+)
+
+type action struct {
+	key   string
+	label string
+	run   func()
+}
+
+// EnableActions provides the targets of the notification actions: socket
+// commands are executed by the socket server, other commands may be sway
+// commands.
+func (n *Notification) EnableActions(server *socketserver.Server, swayClient *sway.Client) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.socketServer = server
+	n.sway = swayClient
+}
+
+func (n *Notification) actions(confs []config.NotificationAction) []action {
+	actions := make([]action, len(confs))
+
+	for i, conf := range confs {
+		actions[i] = action{
+			key:   "action-" + strconv.Itoa(i),
+			label: conf.Label,
+			run: func() {
+				n.runAction(conf)
+			},
+		}
+	}
+
+	return actions
+}
+
+func (n *Notification) runAction(conf config.NotificationAction) {
+	n.mu.Lock()
+	server := n.socketServer
+	swayClient := n.sway
+	n.mu.Unlock()
+
+	if conf.Socket != "" && server != nil {
+		if err := server.Execute(socket.ParseMessage(conf.Socket)); err != nil {
+			common.LogError("Failed to run notification action "+conf.Label, err)
+		}
+	}
+
+	if conf.Command.Command != "" {
+		if err := conf.Command.Run(swayClient); err != nil {
+			common.LogError("Failed to run notification action "+conf.Label, err)
+		}
+	}
+}
+
+func invokeAction(actions []action, key string) {
+	for _, a := range actions {
+		if a.key == key {
+			go a.run()
+			return
+		}
+	}
+}
+
+func actionsList(actions []action) []string {
+	list := make([]string, 0, 2*len(actions))
+
+	for _, a := range actions {
+		list = append(list, a.key, a.label)
+	}
+
+	return list
+}
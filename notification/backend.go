@@ -0,0 +1,34 @@
+package notification
+
+import (
+	"errors"
+
+	"github.com/willoma/swaypanion/config"
+)
+
+var ErrUnknownBackend = errors.New("unknown notification backend")
+
+type backend interface {
+	notify(msg message) (id uint32, err error)
+	close(id uint32) error
+}
+
+// stoppableBackend is a backend which holds resources, released by stop
+// when the backend is replaced.
+type stoppableBackend interface {
+	backend
+	stop()
+}
+
+func (n *Notification) newBackend(backendType config.NotificationBackend) (backend, error) {
+	switch backendType {
+	case config.NotificationBackendDBus:
+		return newDBusBackend()
+	case config.NotificationBackendLog:
+		return &logBackend{}, nil
+	case config.NotificationBackendSocket:
+		return n.broadcast, nil
+	default:
+		return nil, ErrUnknownBackend
+	}
+}
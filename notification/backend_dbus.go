@@ -0,0 +1,140 @@
+package notification
+
+import (
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/willoma/swaypanion/common"
+)
+
+var dbusSignalMatch = []dbus.MatchOption{
+	dbus.WithMatchObjectPath("/org/freedesktop/Notifications"),
+	dbus.WithMatchInterface("org.freedesktop.Notifications"),
+}
+
+type dbusBackend struct {
+	conn    *dbus.Conn
+	signals chan *dbus.Signal
+
+	mu      sync.Mutex
+	actions map[uint32][]action
+}
+
+func newDBusBackend() (*dbusBackend, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, common.Errorf("failed to connect to DBus", err)
+	}
+
+	d := &dbusBackend{
+		conn:    conn,
+		actions: map[uint32][]action{},
+	}
+
+	if err := conn.AddMatchSignal(dbusSignalMatch...); err != nil {
+		common.LogError("Failed to listen to notification signals, actions will not work", err)
+		return d, nil
+	}
+
+	d.signals = make(chan *dbus.Signal, 10)
+	conn.Signal(d.signals)
+
+	go d.listenSignals(d.signals)
+
+	return d, nil
+}
+
+// stop stops listening to notification signals. The session bus connection
+// is shared, the match rule and the signal channel must be removed from it.
+func (d *dbusBackend) stop() {
+	if d.signals == nil {
+		return
+	}
+
+	if err := d.conn.RemoveMatchSignal(dbusSignalMatch...); err != nil {
+		common.LogError("Failed to stop listening to notification signals", err)
+	}
+
+	d.conn.RemoveSignal(d.signals)
+	close(d.signals)
+	d.signals = nil
+}
+
+func (d *dbusBackend) listenSignals(signals chan *dbus.Signal) {
+	for signal := range signals {
+		if len(signal.Body) < 2 {
+			continue
+		}
+
+		id, ok := signal.Body[0].(uint32)
+		if !ok {
+			continue
+		}
+
+		switch signal.Name {
+		case "org.freedesktop.Notifications.ActionInvoked":
+			key, _ := signal.Body[1].(string)
+
+			d.mu.Lock()
+			actions := d.actions[id]
+			d.mu.Unlock()
+
+			invokeAction(actions, key)
+		case "org.freedesktop.Notifications.NotificationClosed":
+			d.mu.Lock()
+			delete(d.actions, id)
+			d.mu.Unlock()
+		}
+	}
+}
+
+func (d *dbusBackend) object() dbus.BusObject {
+	return d.conn.Object(
+		"org.freedesktop.Notifications",
+		"/org/freedesktop/Notifications",
+	)
+}
+
+func (d *dbusBackend) notify(msg message) (uint32, error) {
+	hints := msg.hints
+	if hints == nil {
+		hints = map[string]dbus.Variant{}
+	}
+
+	call := d.object().Call(
+		"org.freedesktop.Notifications.Notify", 0,
+		msg.appName,              // app_name
+		msg.replacesID,           // replaces_id
+		msg.icon,                 // app_icon
+		msg.summary,              // summary
+		msg.body,                 // body
+		actionsList(msg.actions), // actions
+		hints,                    // hints
+		msg.timeout,              // expire_timeout
+	)
+	if call.Err != nil {
+		return 0, call.Err
+	}
+
+	var id uint32
+
+	if len(call.Body) > 0 {
+		id, _ = call.Body[0].(uint32)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(msg.actions) > 0 {
+		d.actions[id] = msg.actions
+	} else {
+		delete(d.actions, id)
+	}
+
+	return id, nil
+}
+
+func (d *dbusBackend) close(id uint32) error {
+	return d.object().Call("org.freedesktop.Notifications.CloseNotification", 0, id).Err
+}
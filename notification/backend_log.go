@@ -0,0 +1,39 @@
+package notification
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/willoma/swaypanion/common"
+)
+
+var lastNotificationID atomic.Uint32
+
+// nextNotificationID returns identifiers for the backends which do not get
+// them from a notification server.
+func nextNotificationID(replacesID uint32) uint32 {
+	if replacesID != 0 {
+		return replacesID
+	}
+
+	return lastNotificationID.Add(1)
+}
+
+type logBackend struct{}
+
+func (*logBackend) notify(msg message) (uint32, error) {
+	id := nextNotificationID(msg.replacesID)
+
+	line := "Notification " + strconv.FormatUint(uint64(id), 10) + " from " + msg.appName + ": " + msg.summary
+	if msg.body != "" {
+		line += " (" + msg.body + ")"
+	}
+
+	common.LogInfo(line)
+
+	return id, nil
+}
+
+func (*logBackend) close(uint32) error {
+	return nil
+}
@@ -0,0 +1,146 @@
+package notification
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+)
+
+type broadcastNotification struct {
+	id      uint32
+	closed  bool
+	entry   HistoryEntry
+	actions []action
+}
+
+func (b broadcastNotification) Equal(other broadcastNotification) bool {
+	return b.id == other.id && b.closed == other.closed && b.entry == other.entry
+}
+
+func (b broadcastNotification) message() socket.Message {
+	if b.closed {
+		return socket.Message{
+			Command: notificationLabel + " closed",
+			Value:   strconv.FormatUint(uint64(b.id), 10),
+		}
+	}
+
+	msg := b.entry.message()
+	msg.Complement = append(msg.Complement, "ID: "+strconv.FormatUint(uint64(b.id), 10))
+
+	for _, a := range b.actions {
+		msg.Complement = append(msg.Complement, "Action: "+a.key+" "+a.label)
+	}
+
+	return msg
+}
+
+// SocketBackend pushes notifications to the swaypanion clients which
+// subscribed to them.
+type SocketBackend struct {
+	subscriptions *common.Pubsub[broadcastNotification]
+
+	mu      sync.Mutex
+	actions map[uint32][]action
+}
+
+func newSocketBackend() *SocketBackend {
+	return &SocketBackend{
+		subscriptions: common.NewPubsub[broadcastNotification](),
+		actions:       map[uint32][]action{},
+	}
+}
+
+func (s *SocketBackend) notify(msg message) (uint32, error) {
+	id := nextNotificationID(msg.replacesID)
+
+	s.mu.Lock()
+	if len(msg.actions) > 0 {
+		s.actions[id] = msg.actions
+	} else {
+		delete(s.actions, id)
+	}
+	s.mu.Unlock()
+
+	s.subscriptions.Publish(broadcastNotification{
+		id: id,
+		entry: HistoryEntry{
+			Time:    time.Now(),
+			App:     msg.appName,
+			Summary: msg.summary,
+			Body:    msg.body,
+			Urgency: msg.urgency(),
+		},
+		actions: msg.actions,
+	})
+
+	return id, nil
+}
+
+func (s *SocketBackend) close(id uint32) error {
+	s.mu.Lock()
+	delete(s.actions, id)
+	s.mu.Unlock()
+
+	s.subscriptions.Publish(broadcastNotification{
+		id:     id,
+		closed: true,
+	})
+
+	return nil
+}
+
+func (s *SocketBackend) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		s.socketSubscribe, notificationsLabel+" subscribe", "Get notifications when the socket notification backend is in use",
+		s.socketUnsubscribe, notificationsLabel+" unsubscribe", "Stop getting notifications",
+		s.socketInvoke, notificationsLabel+" invoke", "Invoke an action of a notification received by subscription", "notification ID", "action key",
+	)
+}
+
+func (s *SocketBackend) socketSubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	s.subscriptions.Subscribe(conn, false, func(value broadcastNotification) {
+		if err := conn.Send(value.message()); err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				s.subscriptions.Unsubscribe(conn)
+				return
+			}
+
+			common.LogError("Failed to send subscribed notification", err)
+		}
+	})
+}
+
+func (s *SocketBackend) socketUnsubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	s.subscriptions.Unsubscribe(conn)
+}
+
+func (s *SocketBackend) socketInvoke(conn *socketserver.Connection, value string, complement []string) {
+	if value == "" || len(complement) == 0 {
+		conn.SendError("missing notification ID or action key")
+		return
+	}
+
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		conn.SendError("failed to convert notification ID to int")
+		return
+	}
+
+	s.mu.Lock()
+	actions, ok := s.actions[uint32(id)]
+	s.mu.Unlock()
+
+	if !ok {
+		conn.SendError("no action for this notification")
+		return
+	}
+
+	invokeAction(actions, complement[0])
+}
@@ -0,0 +1,141 @@
+package notification
+
+import (
+	"sync"
+	"time"
+
+	"github.com/willoma/swaypanion/common"
+)
+
+type DNDState struct {
+	Manual bool
+	Auto   bool
+	Until  time.Time
+}
+
+func (d DNDState) Equal(other DNDState) bool {
+	return d.Manual == other.Manual && d.Auto == other.Auto && d.Until.Equal(other.Until)
+}
+
+func (d DNDState) Enabled() bool {
+	return d.Manual || d.Auto
+}
+
+type DND struct {
+	subscriptions *common.Pubsub[DNDState]
+
+	mu         sync.Mutex
+	state      DNDState
+	untilTimer *time.Timer
+}
+
+func newDND() *DND {
+	return &DND{
+		subscriptions: common.NewPubsub[DNDState](),
+	}
+}
+
+func (d *DND) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.unsafeStopTimer()
+}
+
+func (d *DND) Enabled() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.state.Enabled()
+}
+
+func (d *DND) get() DNDState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.state
+}
+
+func (d *DND) on() DNDState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.unsafeStopTimer()
+	d.state.Manual = true
+
+	return d.unsafePublish()
+}
+
+func (d *DND) off() DNDState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.unsafeStopTimer()
+	d.state.Manual = false
+
+	return d.unsafePublish()
+}
+
+func (d *DND) toggle() DNDState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.unsafeStopTimer()
+	d.state.Manual = !d.state.Manual
+
+	return d.unsafePublish()
+}
+
+func (d *DND) until(duration time.Duration) DNDState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.unsafeStopTimer()
+	d.state.Manual = true
+	d.state.Until = time.Now().Add(duration)
+	d.untilTimer = time.AfterFunc(duration, d.expire)
+
+	return d.unsafePublish()
+}
+
+// SetAuto enables or disables the automatic part of the do-not-disturb mode,
+// independently from the state requested by the user.
+func (d *DND) SetAuto(auto bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.state.Auto = auto
+
+	d.unsafePublish()
+}
+
+func (d *DND) expire() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.state.Until.IsZero() || time.Now().Before(d.state.Until) {
+		// The timer has been replaced or stopped in the meantime
+		return
+	}
+
+	d.untilTimer = nil
+	d.state.Manual = false
+	d.state.Until = time.Time{}
+
+	d.unsafePublish()
+}
+
+func (d *DND) unsafeStopTimer() {
+	if d.untilTimer != nil {
+		d.untilTimer.Stop()
+		d.untilTimer = nil
+	}
+
+	d.state.Until = time.Time{}
+}
+
+func (d *DND) unsafePublish() DNDState {
+	d.subscriptions.Publish(d.state)
+
+	return d.state
+}
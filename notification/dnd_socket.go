@@ -0,0 +1,105 @@
+package notification
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+)
+
+const dndLabel = "dnd"
+
+func (d DNDState) message() socket.Message {
+	msg := socket.Message{
+		Command: dndLabel,
+		Value:   "off",
+	}
+
+	if d.Enabled() {
+		msg.Value = "on"
+	}
+
+	if d.Auto {
+		msg.Complement = append(msg.Complement, "Automatic")
+	}
+
+	if !d.Until.IsZero() {
+		msg.Complement = append(msg.Complement, "Until: "+d.Until.Format(time.TimeOnly))
+	}
+
+	return msg
+}
+
+func (d *DND) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		d.socketGet, dndLabel, "Get current do-not-disturb mode",
+		d.socketGet, dndLabel+" get", "Get current do-not-disturb mode",
+		d.socketOn, dndLabel+" on", "Enable do-not-disturb mode",
+		d.socketOff, dndLabel+" off", "Disable do-not-disturb mode",
+		d.socketToggle, dndLabel+" toggle", "Toggle do-not-disturb mode",
+		d.socketUntil, dndLabel+" until", "Enable do-not-disturb mode for some time", "duration (eg. 1h30m)",
+		d.socketSubscribe, dndLabel+" subscribe", "Get do-not-disturb mode each time it changes",
+		d.socketUnsubscribe, dndLabel+" unsubscribe", "Stop getting do-not-disturb mode on change",
+	)
+}
+
+func (d *DND) socketGet(conn *socketserver.Connection, _ string, _ []string) {
+	if err := conn.Send(d.get().message()); err != nil {
+		common.LogError("Failed to send do-not-disturb mode", err)
+	}
+}
+
+func (d *DND) socketOn(conn *socketserver.Connection, _ string, _ []string) {
+	if err := conn.Send(d.on().message()); err != nil {
+		common.LogError("Failed to send do-not-disturb mode", err)
+	}
+}
+
+func (d *DND) socketOff(conn *socketserver.Connection, _ string, _ []string) {
+	if err := conn.Send(d.off().message()); err != nil {
+		common.LogError("Failed to send do-not-disturb mode", err)
+	}
+}
+
+func (d *DND) socketToggle(conn *socketserver.Connection, _ string, _ []string) {
+	if err := conn.Send(d.toggle().message()); err != nil {
+		common.LogError("Failed to send do-not-disturb mode", err)
+	}
+}
+
+func (d *DND) socketUntil(conn *socketserver.Connection, value string, _ []string) {
+	if value == "" {
+		conn.SendError("missing duration")
+		return
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		conn.SendError("failed to convert argument to duration")
+		return
+	}
+
+	if err := conn.Send(d.until(duration).message()); err != nil {
+		common.LogError("Failed to send do-not-disturb mode", err)
+	}
+}
+
+func (d *DND) socketSubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	d.subscriptions.Subscribe(conn, true, func(value DNDState) {
+		if err := conn.Send(value.message()); err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				d.subscriptions.Unsubscribe(conn)
+				return
+			}
+
+			common.LogError("Failed to send subscribed do-not-disturb mode", err)
+		}
+	})
+}
+
+func (d *DND) socketUnsubscribe(conn *socketserver.Connection, _ string, _ []string) {
+	d.subscriptions.Unsubscribe(conn)
+}
@@ -0,0 +1,223 @@
+package notification
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+)
+
+const (
+	historyFileName        = "notifications.log"
+	historyRotatedFileName = "notifications.log.1"
+)
+
+type HistoryEntry struct {
+	Time    time.Time `json:"time"`
+	App     string    `json:"app"`
+	Summary string    `json:"summary"`
+	Body    string    `json:"body,omitempty"`
+	Urgency string    `json:"urgency"`
+}
+
+func (h HistoryEntry) matches(text string) bool {
+	text = strings.ToLower(text)
+
+	return strings.Contains(strings.ToLower(h.App), text) ||
+		strings.Contains(strings.ToLower(h.Summary), text) ||
+		strings.Contains(strings.ToLower(h.Body), text)
+}
+
+// History is a bounded list of notifications, persisted in an append-only
+// file which is rotated each time it contains the maximum number of entries.
+type History struct {
+	mu          sync.Mutex
+	enabled     bool
+	size        int
+	path        string
+	rotatedPath string
+	fileEntries int
+	entries     []HistoryEntry
+}
+
+func newHistory() *History {
+	h := &History{}
+
+	dir, err := common.StateDir()
+	if err != nil {
+		common.LogError("Failed to find notification history directory", err)
+		return h
+	}
+
+	h.path = filepath.Join(dir, historyFileName)
+	h.rotatedPath = filepath.Join(dir, historyRotatedFileName)
+
+	return h
+}
+
+func (h *History) Reconfigure(conf *config.Notifications) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.enabled = conf.HistoryEnabled != nil && *conf.HistoryEnabled
+	h.size = conf.HistorySize
+
+	if !h.enabled || h.path == "" {
+		h.entries = nil
+		return
+	}
+
+	h.unsafeLoad()
+}
+
+func (h *History) unsafeLoad() {
+	h.entries = nil
+
+	if _, err := h.unsafeLoadFile(h.rotatedPath); err != nil {
+		common.LogError("Failed to read rotated notification history", err)
+	}
+
+	count, err := h.unsafeLoadFile(h.path)
+	if err != nil {
+		common.LogError("Failed to read notification history", err)
+	}
+
+	h.fileEntries = count
+}
+
+func (h *History) unsafeLoadFile(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	defer f.Close()
+
+	var count int
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+
+		count++
+		h.unsafeAppend(entry)
+	}
+
+	return count, scanner.Err()
+}
+
+func (h *History) unsafeAppend(entry HistoryEntry) {
+	if h.size <= 0 {
+		return
+	}
+
+	h.entries = append(h.entries, entry)
+
+	if len(h.entries) > h.size {
+		h.entries = h.entries[len(h.entries)-h.size:]
+	}
+}
+
+func (h *History) add(entry HistoryEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.enabled || h.path == "" || h.size <= 0 {
+		return
+	}
+
+	h.unsafeAppend(entry)
+
+	if h.fileEntries >= h.size {
+		if err := os.Rename(h.path, h.rotatedPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			common.LogError("Failed to rotate notification history", err)
+		}
+
+		h.fileEntries = 0
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		common.LogError("Failed to encode notification history entry", err)
+		return
+	}
+
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		common.LogError("Failed to open notification history", err)
+		return
+	}
+
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		common.LogError("Failed to write notification history", err)
+		return
+	}
+
+	h.fileEntries++
+}
+
+func (h *History) last(n int) []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if n <= 0 || n > len(h.entries) {
+		n = len(h.entries)
+	}
+
+	result := make([]HistoryEntry, n)
+	copy(result, h.entries[len(h.entries)-n:])
+
+	return result
+}
+
+func (h *History) search(text string) []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var result []HistoryEntry
+
+	for _, entry := range h.entries {
+		if entry.matches(text) {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
+
+func (h *History) clear() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = nil
+	h.fileEntries = 0
+
+	if h.path == "" {
+		return nil
+	}
+
+	for _, path := range []string{h.path, h.rotatedPath} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
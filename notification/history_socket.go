@@ -0,0 +1,96 @@
+package notification
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/socket"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+)
+
+const (
+	notificationsLabel = "notifications"
+	notificationLabel  = "notification"
+)
+
+func (h HistoryEntry) message() socket.Message {
+	msg := socket.Message{
+		Command: notificationLabel,
+		Value:   h.Summary,
+		Complement: []string{
+			"Time: " + h.Time.Format(time.DateTime),
+			"App: " + h.App,
+			"Urgency: " + h.Urgency,
+		},
+	}
+
+	if h.Body != "" {
+		msg.Complement = append(msg.Complement, "Body: "+h.Body)
+	}
+
+	return msg
+}
+
+func (h *History) SocketCommands() socketserver.Commands {
+	return socketserver.NewCommands(
+		h.socketHistory, notificationsLabel+" history", "List last notifications", "number of notifications (all if empty)",
+		h.socketSearch, notificationsLabel+" search", "Search notifications containing a text", "text",
+		h.socketClear, notificationsLabel+" clear", "Clear notifications history",
+	)
+}
+
+func (h *History) sendEntries(conn *socketserver.Connection, entries []HistoryEntry) {
+	if len(entries) == 0 {
+		if err := conn.SendString(notificationsLabel, "no notification"); err != nil {
+			common.LogError("Failed to send notifications history", err)
+		}
+
+		return
+	}
+
+	for _, entry := range entries {
+		if err := conn.Send(entry.message()); err != nil {
+			common.LogError("Failed to send notifications history", err)
+			return
+		}
+	}
+}
+
+func (h *History) socketHistory(conn *socketserver.Connection, value string, _ []string) {
+	var n int
+
+	if value != "" {
+		var err error
+
+		n, err = strconv.Atoi(value)
+		if err != nil {
+			conn.SendError("failed to convert argument to int")
+			return
+		}
+	}
+
+	h.sendEntries(conn, h.last(n))
+}
+
+func (h *History) socketSearch(conn *socketserver.Connection, value string, _ []string) {
+	if value == "" {
+		conn.SendError("missing search text")
+		return
+	}
+
+	h.sendEntries(conn, h.search(value))
+}
+
+func (h *History) socketClear(conn *socketserver.Connection, _ string, _ []string) {
+	if err := h.clear(); err != nil {
+		common.LogError("Failed to clear notifications history", err)
+		conn.SendError("failed to clear notifications history")
+
+		return
+	}
+
+	if err := conn.SendString(notificationsLabel, "cleared"); err != nil {
+		common.LogError("Failed to send notifications history", err)
+	}
+}
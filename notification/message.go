@@ -1,8 +1,6 @@
 package notification
 
 import (
-	"github.com/godbus/dbus/v5"
-
 	"github.com/willoma/swaypanion/common"
 	"github.com/willoma/swaypanion/config"
 )
@@ -12,7 +10,9 @@ type MessageNotifier struct {
 
 	disabled bool
 
-	timeout int
+	timeout       int
+	suppressOnDND bool
+	actions       map[string][]config.NotificationAction
 }
 
 func (n *Notification) MessageNotifier() *MessageNotifier {
@@ -29,28 +29,25 @@ func (m *MessageNotifier) Reconfigure(conf config.NotificationSectionMessage) {
 
 	m.disabled = false
 	m.timeout = int(conf.Timeout.Milliseconds())
+	m.suppressOnDND = conf.SuppressOnDND != nil && *conf.SuppressOnDND
+	m.actions = conf.Actions
 }
 
-func (m *MessageNotifier) Notify(message string) {
+func (m *MessageNotifier) Notify(msg string) {
 	if m.disabled {
 		return
 	}
 
-	call := m.notification.dbus.Object(
-		"org.freedesktop.Notifications",
-		"/org/freedesktop/Notifications",
-	).Call(
-		"org.freedesktop.Notifications.Notify", 0,
-		"swaypanion-message",      // app_name
-		uint32(0),                 // replaces_id
-		"",                        // app_icon
-		message,                   // summary
-		"",                        // body
-		[]string{},                // actions
-		map[string]dbus.Variant{}, // hints
-		m.timeout,                 // expire_timeout
-	)
-	if call.Err != nil {
-		common.LogError("Failed to send message notification", call.Err)
+	if m.suppressOnDND && m.notification.dnd.Enabled() {
+		return
+	}
+
+	if _, err := m.notification.send(message{
+		appName: "swaypanion-message",
+		summary: msg,
+		actions: m.notification.actions(m.actions[msg]),
+		timeout: m.timeout,
+	}); err != nil {
+		common.LogError("Failed to send message notification", err)
 	}
 }
@@ -1,21 +1,137 @@
 package notification
 
 import (
+	"sync"
+	"time"
+
 	"github.com/godbus/dbus/v5"
+
 	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/config"
+	socketserver "github.com/willoma/swaypanion/socket/server"
+	"github.com/willoma/swaypanion/sway"
 )
 
+var urgencyLevels = map[string]byte{
+	"low":      0,
+	"normal":   1,
+	"critical": 2,
+}
+
 type Notification struct {
-	dbus *dbus.Conn
+	dnd       *DND
+	history   *History
+	broadcast *SocketBackend
+
+	mu           sync.Mutex
+	backendType  config.NotificationBackend
+	backend      backend
+	socketServer *socketserver.Server
+	sway         *sway.Client
+}
+
+// New prepares the notification system. It starts with the D-Bus backend if
+// the session bus is available, and falls back to the log backend otherwise.
+func New() *Notification {
+	n := &Notification{
+		dnd:       newDND(),
+		history:   newHistory(),
+		broadcast: newSocketBackend(),
+	}
+
+	n.setBackend(config.NotificationBackendDBus)
+
+	return n
 }
 
-func New() (*Notification, error) {
-	dbusConn, err := dbus.SessionBus()
+func (n *Notification) Reconfigure(conf *config.Notifications) {
+	n.history.Reconfigure(conf)
+	n.setBackend(conf.Backend)
+}
+
+func (n *Notification) setBackend(backendType config.NotificationBackend) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.backend != nil && n.backendType == backendType {
+		return
+	}
+
+	b, err := n.newBackend(backendType)
 	if err != nil {
-		return nil, common.Errorf("failed to connect to DBus", err)
+		common.LogError("Failed to use "+string(backendType)+" notification backend, falling back to log backend", err)
+
+		backendType = config.NotificationBackendLog
+		b = &logBackend{}
+	}
+
+	if stoppable, ok := n.backend.(stoppableBackend); ok {
+		stoppable.stop()
+	}
+
+	n.backendType = backendType
+	n.backend = b
+}
+
+func (n *Notification) DND() *DND {
+	return n.dnd
+}
+
+func (n *Notification) History() *History {
+	return n.history
+}
+
+func (n *Notification) SocketBackend() *SocketBackend {
+	return n.broadcast
+}
+
+type message struct {
+	appName    string
+	replacesID uint32
+	icon       string
+	summary    string
+	body       string
+	actions    []action
+	hints      map[string]dbus.Variant
+	timeout    int
+	// transient messages, like level notifications, are not kept in history
+	transient bool
+}
+
+func (m message) urgency() string {
+	if urgency, ok := m.hints["urgency"].Value().(byte); ok {
+		for name, level := range urgencyLevels {
+			if level == urgency {
+				return name
+			}
+		}
+	}
+
+	return "normal"
+}
+
+func (n *Notification) send(msg message) (uint32, error) {
+	if !msg.transient {
+		n.history.add(HistoryEntry{
+			Time:    time.Now(),
+			App:     msg.appName,
+			Summary: msg.summary,
+			Body:    msg.body,
+			Urgency: msg.urgency(),
+		})
 	}
 
-	return &Notification{
-		dbus: dbusConn,
-	}, nil
+	n.mu.Lock()
+	b := n.backend
+	n.mu.Unlock()
+
+	return b.notify(msg)
+}
+
+func (n *Notification) close(id uint32) error {
+	n.mu.Lock()
+	b := n.backend
+	n.mu.Unlock()
+
+	return b.close(id)
 }
@@ -20,8 +20,12 @@ type PercentNotifier struct {
 	formatDisabled string
 	format0        string
 	formats        []string
-	formatStepSize int
-	format100      string
+	iconDisabled   string
+	icon0          string
+	icons          []string
+	hints          map[string]dbus.Variant
+	suppressOnDND  bool
+	actions        []config.NotificationAction
 
 	mu             sync.Mutex
 	notificationID uint32
@@ -44,14 +48,34 @@ func (p *PercentNotifier) Reconfigure(conf config.NotificationSectionPercent) {
 	p.formatDisabled = conf.FormatDisabled
 	p.format0 = conf.Format0
 	p.formats = conf.Formats
+	p.iconDisabled = conf.IconDisabled
+	p.icon0 = conf.Icon0
+	p.icons = conf.Icons
+	p.suppressOnDND = conf.SuppressOnDND != nil && *conf.SuppressOnDND
+	p.actions = conf.Actions
+
+	p.hints = map[string]dbus.Variant{}
+
+	if conf.Urgency != "" {
+		urgency, ok := urgencyLevels[conf.Urgency]
+		if ok {
+			p.hints["urgency"] = dbus.MakeVariant(urgency)
+		} else {
+			common.LogError("Unknown notification urgency "+conf.Urgency, nil)
+		}
+	}
 
-	if len(conf.Formats) == 0 {
-		p.formatStepSize = 100
-		p.formats = []string{""}
-		p.format100 = ""
-	} else {
-		p.formatStepSize = 100 / len(conf.Formats)
-		p.format100 = conf.Formats[len(conf.Formats)-1]
+	if conf.Category != "" {
+		p.hints["category"] = dbus.MakeVariant(conf.Category)
+	}
+
+	if conf.StackTag != "" {
+		p.hints["x-canonical-private-synchronous"] = dbus.MakeVariant(conf.StackTag)
+		p.hints["x-dunst-stack-tag"] = dbus.MakeVariant(conf.StackTag)
+	}
+
+	if conf.SoundName != "" {
+		p.hints["sound-name"] = dbus.MakeVariant(conf.SoundName)
 	}
 }
 
@@ -63,43 +87,80 @@ func (p *PercentNotifier) Notify(percent common.Int) {
 		return
 	}
 
-	var format string
+	if p.suppressOnDND && p.notification.dnd.Enabled() {
+		return
+	}
+
+	var format, icon string
 
 	if percent.Disabled {
 		format = p.formatDisabled
+		icon = p.iconDisabled
 	} else if percent.Value <= 0 {
 		format = p.format0
-	} else if percent.Value >= 100 {
-		format = p.format100
+		icon = p.icon0
 	} else {
-		format = p.formats[percent.Value/p.formatStepSize]
+		format = bucket(percent.Value, p.formats)
+		icon = bucket(percent.Value, p.icons)
 	}
 
 	content := strings.ReplaceAll(format, "{value}", strconv.Itoa(percent.Value))
 
-	hints := map[string]dbus.Variant{"value": dbus.MakeVariant(percent.Value)}
-
-	call := p.notification.dbus.Object(
-		"org.freedesktop.Notifications",
-		"/org/freedesktop/Notifications",
-	).Call(
-		"org.freedesktop.Notifications.Notify", 0,
-		"swaypanion-level", // app_name
-		p.notificationID,   // replaces_id
-		"",                 // app_icon
-		content,            // summary
-		"",                 // body
-		[]string{},         // actions
-		hints,              // hints
-		p.timeout,          // expire_timeout
-	)
-	if call.Err != nil {
-		common.LogError("Failed to send level notification", call.Err)
+	hints := make(map[string]dbus.Variant, len(p.hints)+1)
+	for k, v := range p.hints {
+		hints[k] = v
 	}
 
-	if len(call.Body) > 0 {
-		if id, ok := call.Body[0].(uint32); ok {
-			p.notificationID = id
+	hints["value"] = dbus.MakeVariant(percent.Value)
+
+	var actions []config.NotificationAction
+
+	for _, a := range p.actions {
+		if a.MaxValue == nil || percent.Value <= *a.MaxValue {
+			actions = append(actions, a)
 		}
 	}
+
+	id, err := p.notification.send(message{
+		appName:    "swaypanion-level",
+		replacesID: p.notificationID,
+		icon:       icon,
+		summary:    content,
+		actions:    p.notification.actions(actions),
+		hints:      hints,
+		timeout:    p.timeout,
+		transient:  true,
+	})
+	if err != nil {
+		common.LogError("Failed to send level notification", err)
+		return
+	}
+
+	p.notificationID = id
+}
+
+// Close closes the last level notification, if it is still displayed.
+func (p *PercentNotifier) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.notificationID == 0 {
+		return
+	}
+
+	if err := p.notification.close(p.notificationID); err != nil {
+		common.LogError("Failed to close level notification", err)
+	}
+
+	p.notificationID = 0
+}
+
+// bucket returns the item corresponding to the percent value, each item
+// covering an equal part of the 0-100 range.
+func bucket(value int, items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+
+	return items[min(value*len(items)/100, len(items)-1)]
 }
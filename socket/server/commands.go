@@ -75,6 +75,17 @@ func (c Commands) commandByName(name string) (*Command, error) {
 	return c.commandByShortcut(fields)
 }
 
+// isSubscribe reports whether command is a subscribe command.
+func (c Commands) isSubscribe(command *Command) bool {
+	for name, cmd := range c {
+		if cmd == command {
+			return strings.HasSuffix(name, " subscribe")
+		}
+	}
+
+	return false
+}
+
 type commandCandidate struct {
 	fields  []string
 	command *Command
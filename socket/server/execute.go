@@ -0,0 +1,58 @@
+package socketserver
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/willoma/swaypanion/socket"
+)
+
+// responseBuffer collects the responses to a command executed without a
+// client connection.
+type responseBuffer struct {
+	bytes.Buffer
+}
+
+func (*responseBuffer) Close() error {
+	return nil
+}
+
+func (r *responseBuffer) err() error {
+	for {
+		msg := &socket.Message{}
+		if _, err := msg.ReadFrom(&r.Buffer); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		if msg.Command == "error" {
+			return errors.New(msg.Value)
+		}
+	}
+}
+
+var errSubscribeNotExecutable = errors.New("subscribe commands need a client connection")
+
+// Execute runs a command as if it had been sent by a client, discarding its
+// responses. An error response from the command is returned as an error.
+// Subscribe commands are rejected, because nobody would ever unsubscribe.
+func (s *Server) Execute(msg *socket.Message) error {
+	cmd, err := s.commands.commandByName(msg.Command)
+	if err != nil {
+		return err
+	}
+
+	if s.commands.isSubscribe(cmd) {
+		return errSubscribeNotExecutable
+	}
+
+	buf := &responseBuffer{}
+
+	cmd.Fn(&Connection{conn: buf}, msg.Value, msg.Complement)
+
+	return buf.err()
+}
@@ -2,6 +2,7 @@ package socket
 
 import (
 	"io"
+	"strings"
 	"syscall"
 )
 
@@ -134,3 +135,25 @@ func (m *Message) ReadFrom(r io.Reader) (n int64, err error) {
 		}
 	}
 }
+
+// ParseMessage reads a message written the way users write commands, with
+// ":" separating the command from its value and complements (eg.
+// "volume set:50").
+func ParseMessage(str string) *Message {
+	msg := &Message{}
+
+	for i, field := range strings.Split(str, ":") {
+		field = strings.Join(strings.Fields(field), " ")
+
+		switch i {
+		case fieldCommand:
+			msg.Command = field
+		case fieldValue:
+			msg.Value = field
+		default:
+			msg.Complement = append(msg.Complement, field)
+		}
+	}
+
+	return msg
+}
@@ -0,0 +1,29 @@
+package sway
+
+import (
+	"strings"
+
+	"github.com/joshuarubin/go-sway"
+)
+
+// Display is a physical output, as returned by sway get_outputs. Contrary
+// to Output, it is connected even when disabled.
+type Display = sway.Output
+
+// Displays returns the connected displays, whether they are enabled or not.
+func (c *Client) Displays() ([]Display, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil {
+		return nil, ErrNotConnected
+	}
+
+	return c.client.GetOutputs(c.ctx)
+}
+
+// DisplayDescription returns the make, model and serial number of the
+// display, in the same form as in the sway configuration.
+func DisplayDescription(display Display) string {
+	return strings.Join([]string{display.Make, display.Model, display.Serial}, " ")
+}
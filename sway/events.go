@@ -0,0 +1,262 @@
+package sway
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joshuarubin/go-sway"
+
+	"github.com/willoma/swaypanion/common"
+)
+
+type (
+	Node           = sway.Node
+	Rect           = sway.Rect
+	WorkspaceEvent = sway.WorkspaceEvent
+	WindowEvent    = sway.WindowEvent
+	ModeEvent      = sway.ModeEvent
+	BindingEvent   = sway.BindingEvent
+	InputEvent     = sway.InputEvent
+)
+
+const (
+	WorkspaceEmpty  = sway.WorkspaceEmpty
+	WorkspaceInit   = sway.WorkspaceInit
+	WorkspaceFocus  = sway.WorkspaceFocus
+	WorkspaceMove   = sway.WorkspaceMove
+	WorkspaceReload = sway.WorkspaceReload
+	WorkspaceRename = sway.WorkspaceRename
+	WorkspaceUrgent = sway.WorkspaceUrgent
+
+	WindowClose      = sway.WindowClose
+	WindowFloating   = sway.WindowFloating
+	WindowFocus      = sway.WindowFocus
+	WindowFullscreen = sway.WindowFullscreen
+	WindowMark       = sway.WindowMark
+	WindowMove       = sway.WindowMove
+	WindowNew        = sway.WindowNew
+	WindowTitle      = sway.WindowTitle
+	WindowUrgent     = sway.WindowUrgent
+)
+
+// OutputEvent is sent when outputs are added, removed or changed. Sway does
+// not tell what changed, the outputs must be requested again.
+type OutputEvent struct {
+	Change string `json:"change"`
+}
+
+// The go-sway library does not handle output events, the events connection
+// is implemented here.
+const (
+	ipcMagic = "i3-ipc"
+
+	ipcSubscribe uint32 = 2
+
+	ipcEventWorkspace uint32 = 0x80000000
+	ipcEventOutput    uint32 = 0x80000001
+	ipcEventMode      uint32 = 0x80000002
+	ipcEventWindow    uint32 = 0x80000003
+	ipcEventBinding   uint32 = 0x80000005
+	ipcEventInput     uint32 = 0x80000015
+)
+
+var (
+	errSubscriptionRefused = errors.New("subscription refused by sway")
+	errInvalidMagic        = errors.New("invalid i3-ipc magic string")
+
+	subscribedEvents = []string{"workspace", "output", "mode", "window", "binding", "input"}
+)
+
+type events struct {
+	workspace *common.Events[WorkspaceEvent]
+	output    *common.Events[OutputEvent]
+	mode      *common.Events[ModeEvent]
+	window    *common.Events[WindowEvent]
+	binding   *common.Events[BindingEvent]
+	input     *common.Events[InputEvent]
+}
+
+func newEvents() events {
+	return events{
+		workspace: common.NewEvents[WorkspaceEvent](),
+		output:    common.NewEvents[OutputEvent](),
+		mode:      common.NewEvents[ModeEvent](),
+		window:    common.NewEvents[WindowEvent](),
+		binding:   common.NewEvents[BindingEvent](),
+		input:     common.NewEvents[InputEvent](),
+	}
+}
+
+func (c *Client) WorkspaceEvents() *common.Events[WorkspaceEvent] {
+	return c.events.workspace
+}
+
+func (c *Client) OutputEvents() *common.Events[OutputEvent] {
+	return c.events.output
+}
+
+func (c *Client) ModeEvents() *common.Events[ModeEvent] {
+	return c.events.mode
+}
+
+func (c *Client) WindowEvents() *common.Events[WindowEvent] {
+	return c.events.window
+}
+
+func (c *Client) BindingEvents() *common.Events[BindingEvent] {
+	return c.events.binding
+}
+
+func (c *Client) InputEvents() *common.Events[InputEvent] {
+	return c.events.input
+}
+
+// listenEvents keeps a connection subscribed to sway events, reconnecting
+// each time it is lost, until the client is closed.
+func (c *Client) listenEvents() {
+	for {
+		socketPath := strings.TrimSpace(os.Getenv("SWAYSOCK"))
+		if socketPath == "" {
+			return
+		}
+
+		err := c.receiveEvents(socketPath)
+
+		if c.ctx.Err() != nil {
+			return
+		}
+
+		common.LogError("Lost connection to Sway events, trying again later", err)
+
+		select {
+		case <-time.After(swayRetryDelay):
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Client) receiveEvents(socketPath string) error {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		select {
+		case <-c.ctx.Done():
+		case <-stop:
+		}
+
+		conn.Close()
+	}()
+
+	payload, err := json.Marshal(subscribedEvents)
+	if err != nil {
+		return err
+	}
+
+	if err := writeIPCMessage(conn, ipcSubscribe, payload); err != nil {
+		return err
+	}
+
+	_, payload, err = readIPCMessage(conn)
+	if err != nil {
+		return err
+	}
+
+	var reply struct {
+		Success bool `json:"success"`
+	}
+
+	if err := json.Unmarshal(payload, &reply); err != nil {
+		return err
+	}
+
+	if !reply.Success {
+		return errSubscriptionRefused
+	}
+
+	for {
+		msgType, payload, err := readIPCMessage(conn)
+		if err != nil {
+			return err
+		}
+
+		if err := c.events.publish(msgType, payload); err != nil {
+			common.LogError("Failed to decode Sway event", err)
+		}
+	}
+}
+
+func (e events) publish(msgType uint32, payload []byte) error {
+	switch msgType {
+	case ipcEventWorkspace:
+		return publishEvent(e.workspace, payload)
+	case ipcEventOutput:
+		return publishEvent(e.output, payload)
+	case ipcEventMode:
+		return publishEvent(e.mode, payload)
+	case ipcEventWindow:
+		return publishEvent(e.window, payload)
+	case ipcEventBinding:
+		return publishEvent(e.binding, payload)
+	case ipcEventInput:
+		return publishEvent(e.input, payload)
+	}
+
+	return nil
+}
+
+func publishEvent[T any](e *common.Events[T], payload []byte) error {
+	var event T
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return err
+	}
+
+	e.Publish(event)
+
+	return nil
+}
+
+func writeIPCMessage(w io.Writer, msgType uint32, payload []byte) error {
+	msg := make([]byte, 0, len(ipcMagic)+8+len(payload))
+	msg = append(msg, ipcMagic...)
+	msg = binary.LittleEndian.AppendUint32(msg, uint32(len(payload)))
+	msg = binary.LittleEndian.AppendUint32(msg, msgType)
+	msg = append(msg, payload...)
+
+	_, err := w.Write(msg)
+
+	return err
+}
+
+func readIPCMessage(r io.Reader) (msgType uint32, payload []byte, err error) {
+	header := make([]byte, len(ipcMagic)+8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	if string(header[:len(ipcMagic)]) != ipcMagic {
+		return 0, nil, errInvalidMagic
+	}
+
+	length := binary.LittleEndian.Uint32(header[len(ipcMagic):])
+	msgType = binary.LittleEndian.Uint32(header[len(ipcMagic)+4:])
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return msgType, payload, nil
+}
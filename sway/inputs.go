@@ -0,0 +1,39 @@
+package sway
+
+import "github.com/joshuarubin/go-sway"
+
+type Input = sway.Input
+
+const (
+	InputAdded     = "added"
+	InputRemoved   = "removed"
+	InputXKBKeymap = "xkb_keymap"
+	InputXKBLayout = "xkb_layout"
+
+	InputTypeKeyboard = "keyboard"
+)
+
+// Keyboards returns the input devices which are keyboards.
+func (c *Client) Keyboards() ([]Input, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil {
+		return nil, ErrNotConnected
+	}
+
+	inputs, err := c.client.GetInputs(c.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keyboards := make([]Input, 0, len(inputs))
+
+	for _, input := range inputs {
+		if input.Type == InputTypeKeyboard {
+			keyboards = append(keyboards, input)
+		}
+	}
+
+	return keyboards, nil
+}
@@ -3,6 +3,13 @@ package sway
 import "errors"
 
 func (c *Client) RunCommand(command string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil {
+		return ErrNotConnected
+	}
+
 	resp, err := c.client.RunCommand(c.ctx, command)
 	if err != nil {
 		return err
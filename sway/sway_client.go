@@ -2,6 +2,8 @@ package sway
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/joshuarubin/go-sway"
@@ -10,9 +12,15 @@ import (
 
 const swayRetryDelay = 2 * time.Second
 
+var ErrNotConnected = errors.New("not connected to sway")
+
 type Client struct {
+	mu     sync.Mutex
 	client sway.Client
 
+	events    events
+	connected chan struct{}
+
 	ctx    context.Context
 	cancel context.CancelFunc
 }
@@ -21,21 +29,28 @@ func NewClient() *Client {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	s := &Client{
-		ctx:    ctx,
-		cancel: cancel,
+		events:    newEvents(),
+		connected: make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
 	}
 
 	go s.connect()
+	go s.listenEvents()
 
 	return s
 }
 
 func (s *Client) connect() {
-	var err error
-
 	for {
-		s.client, err = sway.New(s.ctx)
+		client, err := sway.New(s.ctx)
 		if err == nil {
+			s.mu.Lock()
+			s.client = client
+			s.mu.Unlock()
+
+			close(s.connected)
+
 			break
 		}
 
@@ -50,10 +65,33 @@ func (s *Client) connect() {
 	}
 }
 
+// OnConnected runs fn in a new goroutine once the client is connected to
+// sway. It is never run if the client is closed before.
+func (c *Client) OnConnected(fn func()) {
+	go func() {
+		select {
+		case <-c.connected:
+			fn()
+		case <-c.ctx.Done():
+		}
+	}()
+}
+
 func (s *Client) Close() {
 	s.cancel()
 }
 
+func (s *Client) getTree() (*sway.Node, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == nil {
+		return nil, ErrNotConnected
+	}
+
+	return s.client.GetTree(s.ctx)
+}
+
 func CountWindows(node *sway.Node) int {
 	if len(node.Nodes) == 0 && len(node.FloatingNodes) == 0 {
 		return 1
@@ -72,22 +110,28 @@ func CountWindows(node *sway.Node) int {
 	return count
 }
 
-func hasFocus(node *sway.Node) bool {
+// HasFocus tells if the node or one of its descendants has the focus.
+func HasFocus(node *sway.Node) bool {
 	if node.Focused {
 		return true
 	}
 
 	for _, subnode := range node.Nodes {
-		if hasFocus(subnode) {
+		if HasFocus(subnode) {
 			return true
 		}
 	}
 
 	for _, subnode := range node.FloatingNodes {
-		if hasFocus(subnode) {
+		if HasFocus(subnode) {
 			return true
 		}
 	}
 
 	return false
 }
+
+// Tree returns the whole tree of sway nodes.
+func (c *Client) Tree() (*sway.Node, error) {
+	return c.getTree()
+}
@@ -0,0 +1,288 @@
+// Package swaytest provides an in-process fake sway IPC server, for testing
+// code which uses the sway package.
+package swaytest
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	gosway "github.com/joshuarubin/go-sway"
+
+	"github.com/willoma/swaypanion/sway"
+)
+
+const (
+	ipcMagic = "i3-ipc"
+
+	ipcRunCommand    uint32 = 0
+	ipcGetWorkspaces uint32 = 1
+	ipcSubscribe     uint32 = 2
+	ipcGetOutputs    uint32 = 3
+	ipcGetTree       uint32 = 4
+	ipcGetVersion    uint32 = 7
+	ipcGetInputs     uint32 = 100
+)
+
+// Event types, to be used with SendEvent.
+const (
+	EventWorkspace uint32 = 0x80000000
+	EventOutput    uint32 = 0x80000001
+	EventMode      uint32 = 0x80000002
+	EventWindow    uint32 = 0x80000003
+	EventBinding   uint32 = 0x80000005
+	EventInput     uint32 = 0x80000015
+)
+
+const connectTimeout = 5 * time.Second
+
+var (
+	errInvalidMagic = errors.New("invalid i3-ipc magic string")
+
+	eventNames = map[uint32]string{
+		EventWorkspace: "workspace",
+		EventOutput:    "output",
+		EventMode:      "mode",
+		EventWindow:    "window",
+		EventBinding:   "binding",
+		EventInput:     "input",
+	}
+)
+
+type connection struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	events []string
+}
+
+func (c *connection) write(msgType uint32, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	msg := make([]byte, 0, len(ipcMagic)+8+len(payload))
+	msg = append(msg, ipcMagic...)
+	msg = binary.LittleEndian.AppendUint32(msg, uint32(len(payload)))
+	msg = binary.LittleEndian.AppendUint32(msg, msgType)
+	msg = append(msg, payload...)
+
+	_, err := c.conn.Write(msg)
+
+	return err
+}
+
+// Server is a fake sway IPC server. It serves a configurable tree, records
+// the payloads of RUN_COMMAND messages and sends events to the subscribed
+// connections.
+type Server struct {
+	t        testing.TB
+	listener net.Listener
+
+	mu          sync.Mutex
+	tree        *gosway.Node
+	commands    []string
+	connections []*connection
+}
+
+// NewServer starts a fake server and points $SWAYSOCK to it. The server is
+// stopped when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "sway.sock")
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen on fake sway socket: %v", err)
+	}
+
+	s := &Server{
+		t:        t,
+		listener: listener,
+		tree:     Root(),
+	}
+
+	t.Setenv("SWAYSOCK", path)
+	t.Cleanup(s.close)
+
+	go s.accept()
+
+	return s
+}
+
+// Client returns a sway client connected to the server, once both its
+// command and event connections are established.
+func (s *Server) Client() *sway.Client {
+	s.t.Helper()
+
+	client := sway.NewClient()
+	s.t.Cleanup(client.Close)
+
+	deadline := time.Now().Add(connectTimeout)
+
+	for {
+		_, err := client.Tree()
+		if err == nil && s.subscribed() {
+			return client
+		}
+
+		if time.Now().After(deadline) {
+			s.t.Fatalf("sway client did not connect to fake server: %v", err)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// SetTree replaces the tree served by GET_TREE.
+func (s *Server) SetTree(root *gosway.Node) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tree = root
+}
+
+// Commands returns the payloads of the RUN_COMMAND messages received since
+// the server started or since the last call to ResetCommands.
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.commands)
+}
+
+func (s *Server) ResetCommands() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands = nil
+}
+
+// SendEvent sends an event to the connections subscribed to its type.
+func (s *Server) SendEvent(eventType uint32, event any) {
+	s.t.Helper()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.t.Fatalf("failed to encode fake sway event: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.connections {
+		if slices.Contains(conn.events, eventNames[eventType]) {
+			if err := conn.write(eventType, payload); err != nil {
+				s.t.Errorf("failed to send fake sway event: %v", err)
+			}
+		}
+	}
+}
+
+func (s *Server) subscribed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.ContainsFunc(s.connections, func(c *connection) bool {
+		return len(c.events) > 0
+	})
+}
+
+func (s *Server) close() {
+	s.listener.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.connections {
+		conn.conn.Close()
+	}
+}
+
+func (s *Server) accept() {
+	for {
+		netConn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		conn := &connection{conn: netConn}
+
+		s.mu.Lock()
+		s.connections = append(s.connections, conn)
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn *connection) {
+	for {
+		msgType, payload, err := readMessage(conn.conn)
+		if err != nil {
+			return
+		}
+
+		reply, err := s.reply(conn, msgType, payload)
+		if err != nil {
+			s.t.Errorf("failed to build fake sway reply: %v", err)
+			return
+		}
+
+		if err := conn.write(msgType, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) reply(conn *connection, msgType uint32, payload []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch msgType {
+	case ipcRunCommand:
+		s.commands = append(s.commands, string(payload))
+		return []byte(`[{"success":true}]`), nil
+	case ipcSubscribe:
+		if err := json.Unmarshal(payload, &conn.events); err != nil {
+			return []byte(`{"success":false}`), nil
+		}
+
+		return []byte(`{"success":true}`), nil
+	case ipcGetTree:
+		return json.Marshal(s.tree)
+	case ipcGetWorkspaces, ipcGetOutputs, ipcGetInputs:
+		return []byte(`[]`), nil
+	case ipcGetVersion:
+		return []byte(`{"major":1,"minor":9,"patch":0,"human_readable":"swaytest"}`), nil
+	default:
+		return []byte(`{}`), nil
+	}
+}
+
+func readMessage(r io.Reader) (msgType uint32, payload []byte, err error) {
+	header := make([]byte, len(ipcMagic)+8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	if string(header[:len(ipcMagic)]) != ipcMagic {
+		return 0, nil, errInvalidMagic
+	}
+
+	length := binary.LittleEndian.Uint32(header[len(ipcMagic):])
+	msgType = binary.LittleEndian.Uint32(header[len(ipcMagic)+4:])
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return msgType, payload, nil
+}
@@ -0,0 +1,99 @@
+package swaytest
+
+import gosway "github.com/joshuarubin/go-sway"
+
+// Root builds a tree from outputs, giving a unique ID to each node.
+func Root(outputs ...*gosway.Node) *gosway.Node {
+	root := &gosway.Node{
+		Type:  gosway.NodeRoot,
+		Name:  "root",
+		Nodes: append([]*gosway.Node{scratchpad()}, outputs...),
+	}
+
+	var id int64
+
+	var number func(node *gosway.Node)
+	number = func(node *gosway.Node) {
+		id++
+		node.ID = id
+
+		for _, subnode := range node.Nodes {
+			number(subnode)
+		}
+
+		for _, subnode := range node.FloatingNodes {
+			number(subnode)
+		}
+	}
+
+	number(root)
+
+	return root
+}
+
+func scratchpad() *gosway.Node {
+	return &gosway.Node{
+		Type: gosway.NodeOutput,
+		Name: "__i3",
+		Nodes: []*gosway.Node{{
+			Type: gosway.NodeWorkspace,
+			Name: "__i3_scratch",
+		}},
+	}
+}
+
+// Output builds an output with its workspaces.
+func Output(name string, workspaces ...*gosway.Node) *gosway.Node {
+	return &gosway.Node{
+		Type:  gosway.NodeOutput,
+		Name:  name,
+		Nodes: workspaces,
+	}
+}
+
+// Workspace builds a workspace with its tiled containers.
+func Workspace(name string, nodes ...*gosway.Node) *gosway.Node {
+	return &gosway.Node{
+		Type:   gosway.NodeWorkspace,
+		Name:   name,
+		Layout: gosway.LayoutSplitH,
+		Nodes:  nodes,
+	}
+}
+
+// Container builds a container with its children.
+func Container(layout gosway.Layout, nodes ...*gosway.Node) *gosway.Node {
+	return &gosway.Node{
+		Type:   gosway.NodeCon,
+		Layout: layout,
+		Nodes:  nodes,
+	}
+}
+
+// Window builds a wayland window.
+func Window(appID, title string) *gosway.Node {
+	return &gosway.Node{
+		Type:  gosway.NodeCon,
+		Name:  title,
+		AppID: &appID,
+	}
+}
+
+// XWindow builds an X window.
+func XWindow(class, instance, title string) *gosway.Node {
+	return &gosway.Node{
+		Type: gosway.NodeCon,
+		Name: title,
+		WindowProperties: &gosway.WindowProperties{
+			Title:    title,
+			Class:    class,
+			Instance: instance,
+		},
+	}
+}
+
+// Focused marks the node as focused.
+func Focused(node *gosway.Node) *gosway.Node {
+	node.Focused = true
+	return node
+}
@@ -2,14 +2,25 @@ package sway
 
 import (
 	"errors"
+	"slices"
 
 	"github.com/joshuarubin/go-sway"
 )
 
+const (
+	LayoutSplitH  = sway.LayoutSplitH
+	LayoutSplitV  = sway.LayoutSplitV
+	LayoutStacked = sway.LayoutStacked
+	LayoutTabbed  = sway.LayoutTabbed
+)
+
+// ScratchpadName is the name of the workspace containing hidden windows.
+const ScratchpadName = "__i3_scratch"
+
 var ErrCurrentWindowNotFound = errors.New("current window not found")
 
 func (c *Client) FocusedNode() (*sway.Node, error) {
-	root, err := c.client.GetTree(c.ctx)
+	root, err := c.getTree()
 	if err != nil {
 		return nil, err
 	}
@@ -23,3 +34,187 @@ func (c *Client) FocusedNode() (*sway.Node, error) {
 	return focused, nil
 
 }
+
+func (c *Client) Windows() ([]*sway.Node, error) {
+	root, err := c.getTree()
+	if err != nil {
+		return nil, err
+	}
+
+	return Windows(root), nil
+}
+
+func Windows(node *sway.Node) []*sway.Node {
+	if IsWindow(node) {
+		return []*sway.Node{node}
+	}
+
+	var windows []*sway.Node
+
+	for _, subnode := range node.Nodes {
+		windows = append(windows, Windows(subnode)...)
+	}
+
+	for _, subnode := range node.FloatingNodes {
+		windows = append(windows, Windows(subnode)...)
+	}
+
+	return windows
+}
+
+func IsWindow(node *sway.Node) bool {
+	return (node.Type == sway.NodeCon || node.Type == sway.NodeFloatingCon) &&
+		len(node.Nodes) == 0 && len(node.FloatingNodes) == 0
+}
+
+func IsFullscreen(node *sway.Node) bool {
+	return node.FullscreenMode != sway.FullscreenNone
+}
+
+// WindowsWorkspaces returns the name of the workspace containing each window,
+// indexed by window ID. Hidden windows are in the "__i3_scratch" workspace.
+func (c *Client) WindowsWorkspaces() (map[int64]string, error) {
+	root, err := c.getTree()
+	if err != nil {
+		return nil, err
+	}
+
+	return windowsWorkspaces(root), nil
+}
+
+// WindowsWithWorkspaces returns all windows, along with the name of the
+// workspace containing each of them, indexed by window ID.
+func (c *Client) WindowsWithWorkspaces() ([]*sway.Node, map[int64]string, error) {
+	root, err := c.getTree()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return Windows(root), windowsWorkspaces(root), nil
+}
+
+func windowsWorkspaces(root *sway.Node) map[int64]string {
+	workspaces := map[int64]string{}
+
+	for _, output := range root.Nodes {
+		for _, workspace := range output.Nodes {
+			if workspace.Type != sway.NodeWorkspace {
+				continue
+			}
+
+			for _, window := range Windows(workspace) {
+				workspaces[window.ID] = workspace.Name
+			}
+		}
+	}
+
+	return workspaces
+}
+
+// WindowPosition is the place of a window in the tree.
+type WindowPosition struct {
+	Window    *sway.Node
+	Parent    *sway.Node
+	Workspace *sway.Node
+}
+
+// WindowPositions returns the position of each window in the tree, indexed by
+// window ID. Hidden windows are in the "__i3_scratch" workspace.
+func WindowPositions(root *sway.Node) map[int64]WindowPosition {
+	positions := map[int64]WindowPosition{}
+
+	var walk func(node, workspace *sway.Node)
+	walk = func(node, workspace *sway.Node) {
+		if node.Type == sway.NodeWorkspace {
+			workspace = node
+		}
+
+		for _, subnode := range slices.Concat(node.Nodes, node.FloatingNodes) {
+			if IsWindow(subnode) {
+				positions[subnode.ID] = WindowPosition{subnode, node, workspace}
+			}
+
+			walk(subnode, workspace)
+		}
+	}
+
+	walk(root, nil)
+
+	return positions
+}
+
+// AppName returns the app_id of a wayland window or the class of an X window.
+func AppName(window *sway.Node) string {
+	if window.AppID != nil && *window.AppID != "" {
+		return *window.AppID
+	}
+
+	if window.WindowProperties != nil {
+		return window.WindowProperties.Class
+	}
+
+	return ""
+}
+
+// HiddenWindows returns the windows in the scratchpad.
+func (c *Client) HiddenWindows() ([]*sway.Node, error) {
+	root, err := c.getTree()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, output := range root.Nodes {
+		for _, workspace := range output.Nodes {
+			if workspace.Type == sway.NodeWorkspace && workspace.Name == ScratchpadName {
+				return Windows(workspace), nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// FocusedWindow returns the focused window, along with its parent container
+// and its workspace.
+func (c *Client) FocusedWindow() (window, parent, workspace *sway.Node, err error) {
+	root, err := c.getTree()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var find func(node, nodeParent, nodeWorkspace *sway.Node) bool
+	find = func(node, nodeParent, nodeWorkspace *sway.Node) bool {
+		if node.Type == sway.NodeWorkspace {
+			nodeWorkspace = node
+		}
+
+		if node.Focused && IsWindow(node) {
+			window, parent, workspace = node, nodeParent, nodeWorkspace
+			return true
+		}
+
+		for _, subnode := range node.Nodes {
+			if find(subnode, node, nodeWorkspace) {
+				return true
+			}
+		}
+
+		for _, subnode := range node.FloatingNodes {
+			if find(subnode, node, nodeWorkspace) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if !find(root, nil, nil) {
+		return nil, nil, nil, ErrCurrentWindowNotFound
+	}
+
+	return window, parent, workspace, nil
+}
+
+func IsFloating(node *sway.Node) bool {
+	return node.Type == sway.NodeFloatingCon
+}
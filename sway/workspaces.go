@@ -4,20 +4,25 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/joshuarubin/go-sway"
+
+	"github.com/willoma/swaypanion/common"
 )
 
 var (
 	ErrCurrentWorkspaceNotFound = errors.New("current workspace not found")
+	ErrDuplicateWorkspaceNumber = errors.New("duplicate workspace number")
+	ErrWorkspaceNameTaken       = errors.New("workspace name already taken by another workspace")
 
-	reWorkspaceName = regexp.MustCompile("([0-9]+): (.*)")
+	reWorkspaceName = regexp.MustCompile("^([0-9]+): (.*)")
 )
 
 func (c *Client) CurrentWorkspace() (rankInOutput int, workspaces []*sway.Node, err error) {
-	root, err := c.client.GetTree(c.ctx)
+	root, err := c.getTree()
 	if err != nil {
 		return 0, nil, err
 	}
@@ -39,7 +44,7 @@ func (c *Client) CurrentWorkspace() (rankInOutput int, workspaces []*sway.Node,
 
 			workspaces = append(workspaces, workspace)
 
-			if hasFocus(workspace) {
+			if HasFocus(workspace) {
 				focusedWorkspaceRank = i
 			}
 		}
@@ -52,24 +57,207 @@ func (c *Client) CurrentWorkspace() (rankInOutput int, workspaces []*sway.Node,
 	return 0, nil, ErrCurrentWorkspaceNotFound
 }
 
-func (c *Client) RenameWorkspacesInOrder(workspaces []*sway.Node) error {
-	for i := len(workspaces) - 1; i >= 0; i-- {
-		_, name := ExtractWorkspaceName(workspaces[i])
-		if err := c.RunCommand(fmt.Sprintf(
-			`rename workspace %q to %q"`,
-			workspaces[i].Name, MakeWorkspaceName(i+1, name),
-		)); err != nil {
+type Output struct {
+	Name       string
+	Workspaces []*sway.Node
+	// Visible is the ID of the workspace currently shown on the output
+	Visible int64
+}
+
+// Outputs returns the outputs with their workspaces, in order.
+func (c *Client) Outputs() ([]Output, error) {
+	root, err := c.getTree()
+	if err != nil {
+		return nil, err
+	}
+
+	return outputs(root), nil
+}
+
+// CurrentOutput returns all outputs, with the rank of the focused output and
+// the rank of the focused workspace in this output.
+func (c *Client) CurrentOutput() (outputRank, workspaceRank int, outputs []Output, err error) {
+	outputs, err = c.Outputs()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	for i, output := range outputs {
+		for j, workspace := range output.Workspaces {
+			if HasFocus(workspace) {
+				return i, j, outputs, nil
+			}
+		}
+	}
+
+	return 0, 0, nil, ErrCurrentWorkspaceNotFound
+}
+
+func outputs(root *sway.Node) []Output {
+	var outputs []Output
+
+	for _, output := range root.Nodes {
+		if output.Type != sway.NodeOutput || output.Name == "__i3" {
+			continue
+		}
+
+		o := Output{Name: output.Name}
+
+		if len(output.Focus) > 0 {
+			o.Visible = output.Focus[0]
+		}
+
+		for _, workspace := range output.Nodes {
+			if workspace.Type == sway.NodeWorkspace {
+				o.Workspaces = append(o.Workspaces, workspace)
+			}
+		}
+
+		outputs = append(outputs, o)
+	}
+
+	return outputs
+}
+
+// RenumberWorkspaces gives new numbers to workspaces, keeping their names.
+// The renames are ordered so that two workspaces never have the same number,
+// a temporary number is used when workspaces need to swap their numbers.
+// The new numbers must be distinct. As workspace names are global in sway,
+// the new names must not be taken by workspaces which are not renumbered, and
+// temporary numbers are above all existing numbers.
+func (c *Client) RenumberWorkspaces(numbers map[*sway.Node]int) error {
+	type renumbering struct {
+		workspace *sway.Node
+		current   int
+		target    int
+	}
+
+	var (
+		pending []*renumbering
+		used    = map[int]int{}
+		maxNum  int
+	)
+
+	targets := map[int]struct{}{}
+
+	renumbered := make(map[string]struct{}, len(numbers))
+
+	for workspace := range numbers {
+		renumbered[workspace.Name] = struct{}{}
+	}
+
+	root, err := c.getTree()
+	if err != nil {
+		return err
+	}
+
+	others := map[string]struct{}{}
+
+	for _, output := range outputs(root) {
+		for _, workspace := range output.Workspaces {
+			if _, ok := renumbered[workspace.Name]; ok {
+				continue
+			}
+
+			others[workspace.Name] = struct{}{}
+
+			num, _ := ExtractWorkspaceName(workspace)
+			maxNum = max(maxNum, num)
+		}
+	}
+
+	for workspace, target := range numbers {
+		if _, ok := targets[target]; ok {
+			return common.Errorf(strconv.Itoa(target), ErrDuplicateWorkspaceNumber)
+		}
+
+		current, name := ExtractWorkspaceName(workspace)
+		if _, ok := others[strconv.Itoa(target)+": "+name]; ok {
+			return common.Errorf(strconv.Itoa(target)+": "+name, ErrWorkspaceNameTaken)
+		}
+
+		targets[target] = struct{}{}
+
+		if current == target {
+			continue
+		}
+
+		pending = append(pending, &renumbering{workspace, current, target})
+
+		if current != -1 {
+			used[current]++
+		}
+
+		maxNum = max(maxNum, current, target)
+	}
+
+	// Deterministic order, easier to follow in sway logs
+	slices.SortFunc(pending, func(a, b *renumbering) int {
+		return a.target - b.target
+	})
+
+	rename := func(r *renumbering, num int) error {
+		_, name := ExtractWorkspaceName(r.workspace)
+		if err := c.RenameWorkspace(r.workspace, MakeWorkspaceName(num, name)); err != nil {
 			return err
 		}
+
+		if r.current != -1 {
+			used[r.current]--
+		}
+
+		used[num]++
+		r.current = num
+		r.workspace = &sway.Node{Name: strconv.Itoa(num) + ": " + name}
+
+		return nil
+	}
+
+	for len(pending) > 0 {
+		var progress bool
+
+		for i := 0; i < len(pending); i++ {
+			r := pending[i]
+			if used[r.target] > 0 {
+				continue
+			}
+
+			if err := rename(r, r.target); err != nil {
+				return err
+			}
+
+			pending = append(pending[:i], pending[i+1:]...)
+			i--
+			progress = true
+		}
+
+		if !progress {
+			// Numbers are swapped, move one workspace out of the way
+			maxNum++
+			if err := rename(pending[0], maxNum); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// RenameWorkspace renames a workspace, newName must be escaped as done by
+// MakeWorkspaceName. Nothing is done if the name does not change.
+func (c *Client) RenameWorkspace(workspace *sway.Node, newName string) error {
+	oldName := escapeName(workspace.Name)
+	if oldName == newName {
+		return nil
+	}
+
+	return c.RunCommand(fmt.Sprintf(`rename workspace "%s" to "%s"`, oldName, newName))
+}
+
 func ExtractWorkspaceName(workspace *sway.Node) (num int, name string) {
 	matches := reWorkspaceName.FindStringSubmatch(workspace.Name)
 	if matches == nil {
-		return -1, name
+		return -1, workspace.Name
 	}
 
 	num, err := strconv.Atoi(matches[1])
@@ -81,5 +269,9 @@ func ExtractWorkspaceName(workspace *sway.Node) (num int, name string) {
 }
 
 func MakeWorkspaceName(num int, name string) string {
-	return strconv.Itoa(num) + ": " + strings.ReplaceAll(name, `"`, `\"`)
+	return strconv.Itoa(num) + ": " + escapeName(name)
+}
+
+func escapeName(name string) string {
+	return strings.ReplaceAll(name, `"`, `\"`)
 }
@@ -0,0 +1,120 @@
+package sway_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	gosway "github.com/joshuarubin/go-sway"
+
+	"github.com/willoma/swaypanion/sway"
+	"github.com/willoma/swaypanion/sway/swaytest"
+)
+
+func TestRenumberWorkspaces(t *testing.T) {
+	server := swaytest.NewServer(t)
+	client := server.Client()
+
+	a := &gosway.Node{Name: "1: a"}
+	b := &gosway.Node{Name: "2: b"}
+	c := &gosway.Node{Name: "c"}
+
+	// a and b swap their numbers, c gets one
+	if err := client.RenumberWorkspaces(map[*gosway.Node]int{a: 2, b: 1, c: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`rename workspace "c" to "3: c"`,
+		`rename workspace "2: b" to "4: b"`,
+		`rename workspace "1: a" to "2: a"`,
+		`rename workspace "4: b" to "1: b"`,
+	}
+
+	if commands := server.Commands(); !slices.Equal(commands, want) {
+		t.Errorf("unexpected sway commands\n got: %q\nwant: %q", commands, want)
+	}
+}
+
+func TestRenumberWorkspacesDuplicate(t *testing.T) {
+	server := swaytest.NewServer(t)
+	client := server.Client()
+
+	err := client.RenumberWorkspaces(map[*gosway.Node]int{
+		{Name: "1: a"}: 2,
+		{Name: "2: b"}: 2,
+	})
+	if err == nil {
+		t.Error("expected an error for duplicate numbers")
+	}
+
+	if commands := server.Commands(); len(commands) != 0 {
+		t.Errorf("unexpected sway commands: %q", commands)
+	}
+}
+
+func TestRenumberWorkspacesOtherOutputs(t *testing.T) {
+	server := swaytest.NewServer(t)
+
+	a := swaytest.Workspace("1: a")
+	b := swaytest.Workspace("2: b")
+
+	server.SetTree(swaytest.Root(
+		swaytest.Output("DP-1", a, b),
+		swaytest.Output("DP-2", swaytest.Workspace("3: b"), swaytest.Workspace("1: c")),
+	))
+
+	client := server.Client()
+
+	// The temporary number must not clash with the workspaces of DP-2
+	if err := client.RenumberWorkspaces(map[*gosway.Node]int{a: 2, b: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`rename workspace "2: b" to "4: b"`,
+		`rename workspace "1: a" to "2: a"`,
+		`rename workspace "4: b" to "1: b"`,
+	}
+
+	if commands := server.Commands(); !slices.Equal(commands, want) {
+		t.Errorf("unexpected sway commands\n got: %q\nwant: %q", commands, want)
+	}
+
+	server.ResetCommands()
+
+	// "1: c" is on DP-2, which is not renumbered
+	if err := client.RenumberWorkspaces(map[*gosway.Node]int{
+		swaytest.Workspace("2: c"): 1,
+	}); err == nil {
+		t.Error("expected an error for a name taken on another output")
+	}
+
+	if commands := server.Commands(); len(commands) != 0 {
+		t.Errorf("unexpected sway commands: %q", commands)
+	}
+}
+
+func TestWindowEvents(t *testing.T) {
+	server := swaytest.NewServer(t)
+	client := server.Client()
+
+	received := make(chan sway.WindowEvent, 1)
+	client.WindowEvents().Subscribe(t, func(event sway.WindowEvent) {
+		received <- event
+	})
+
+	server.SendEvent(swaytest.EventWindow, sway.WindowEvent{
+		Change:    sway.WindowNew,
+		Container: *swaytest.Window("foot", "term"),
+	})
+
+	select {
+	case event := <-received:
+		if event.Change != sway.WindowNew || *event.Container.AppID != "foot" {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("event not received")
+	}
+}
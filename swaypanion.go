@@ -27,10 +27,7 @@ func New() (*Swaypanion, error) {
 		return nil, err
 	}
 
-	notif, err := notification.New()
-	if err != nil {
-		return nil, err
-	}
+	notif := notification.New()
 
 	coreNotifier := notif.MessageNotifier()
 
@@ -47,12 +44,31 @@ func New() (*Swaypanion, error) {
 		coreNotifier: coreNotifier,
 	}
 
+	notif.EnableActions(sock, s.sway)
+
 	s.socketserver.AddCommands(conf.SocketCommands())
 
+	apps := modules.NewApps(conf.Apps, s.sway)
+
 	s.register(modules.NewBacklight(conf.Backlight, notif))
-	s.register(modules.NewPlayer(conf.Player, s.sway))
+	s.register(modules.NewPlayer(conf.Player, s.sway, apps))
 	s.register(modules.NewVolume(conf.Volume, notif))
 	s.register(modules.NewSwayNodes(conf.SwayNodes, s.sway))
+	s.register(modules.NewFocusHistory(conf.FocusHistory, s.sway))
+	s.register(modules.NewFocusedWindow(s.sway))
+	s.register(apps)
+	s.register(modules.NewRules(conf.Rules, s.sway))
+	s.register(modules.NewSwallow(conf.Swallow, s.sway))
+	s.register(modules.NewLayouts(s.sway, apps))
+	s.register(modules.NewBookmarks(conf.Bookmarks, s.sway, apps))
+	s.register(notif.DND())
+	s.register(notif.History())
+	s.register(notif.SocketBackend())
+	s.register(modules.NewDNDAuto(conf.DND, s.sway, notif))
+	s.register(modules.NewIdleInhibit(conf.IdleInhibit, s.sway))
+	s.register(modules.NewKeyboard(conf.Keyboard, s.sway))
+	s.register(modules.NewOutputProfiles(conf.Outputs, s.sway, notif))
+	s.register(modules.NewUrgent(conf.Urgent, s.sway, notif))
 
 	s.reloadConfig(conf)
 	s.stopConfigReload = conf.ListenReload(s.reloadConfig)
@@ -89,6 +105,7 @@ func (s *Swaypanion) Stop() error {
 }
 
 func (s *Swaypanion) reloadConfig(conf *config.Config) {
+	s.notification.Reconfigure(conf.Notifications)
 	s.coreNotifier.Reconfigure(conf.CoreMessages)
 }
 
@@ -13,6 +13,12 @@ type config struct {
 	Backlight configPercent `yaml:"backlight"`
 	Player    configString  `yaml:"player"`
 	Volume    configPercent `yaml:"volume"`
+	Hidden    configCount   `yaml:"hidden"`
+
+	IdleInhibit configString   `yaml:"idle_inhibit"`
+	Keyboard    configKeyboard `yaml:"keyboard"`
+	Urgent      configCount    `yaml:"urgent"`
+	Window      configWindow   `yaml:"window"`
 }
 
 var defaultConfig = &config{
@@ -51,6 +57,50 @@ var defaultConfig = &config{
 		TooltipFormat0: "",
 		TooltipFormats: []string{""},
 	},
+	Hidden: configCount{
+		Icon:          "",
+		TextFormat:    "{icon} {value}",
+		TooltipFormat: "{value} hidden window(s)",
+	},
+	IdleInhibit: configString{
+		Icons: map[string]string{
+			"on":  "",
+			"off": "",
+		},
+		FormatText:    "{icon}",
+		FormatTooltip: "Idle inhibition: {status}",
+	},
+	Keyboard: configKeyboard{
+		ShortNames: map[string]string{
+			"English (US)": "us",
+			"English (UK)": "gb",
+			"French":       "fr",
+			"German":       "de",
+			"Spanish":      "es",
+			"Italian":      "it",
+		},
+		FormatText:    " {short}",
+		FormatTooltip: "{name}",
+	},
+	Urgent: configCount{
+		Icon:          "",
+		TextFormat:    "{icon} {value}",
+		TooltipFormat: "Urgent: {apps}",
+	},
+	Window: configWindow{
+		configString: configString{
+			Icons: map[string]string{
+				"default": "",
+				"none":    "",
+				"firefox": "",
+				"foot":    "",
+			},
+			FormatText:    "{icon}{title? }{title}",
+			FormatTooltip: "{app}{full_title?\n}{full_title}",
+		},
+		MaxLength: 50,
+		Ellipsis:  "…",
+	},
 }
 
 func readConfig(configPath string) (*config, error) {
@@ -113,4 +163,9 @@ func (c *config) applyDefault() {
 	c.Backlight = c.Backlight.applyDefault(defaultConfig.Backlight)
 	c.Player = c.Player.applyDefault(defaultConfig.Player)
 	c.Volume = c.Volume.applyDefault(defaultConfig.Volume)
+	c.Hidden = c.Hidden.applyDefault(defaultConfig.Hidden)
+	c.IdleInhibit = c.IdleInhibit.applyDefault(defaultConfig.IdleInhibit)
+	c.Keyboard = c.Keyboard.applyDefault(defaultConfig.Keyboard)
+	c.Urgent = c.Urgent.applyDefault(defaultConfig.Urgent)
+	c.Window = c.Window.applyDefault(defaultConfig.Window)
 }
@@ -0,0 +1,59 @@
+package waybar
+
+import "github.com/willoma/swaypanion/common"
+
+type configCount struct {
+	Icon0          string `yaml:"icon0"`
+	Icon           string `yaml:"icon"`
+	TextFormat0    string `yaml:"text_format0"`
+	TextFormat     string `yaml:"text_format"`
+	TooltipFormat0 string `yaml:"tooltip_format0"`
+	TooltipFormat  string `yaml:"tooltip_format"`
+}
+
+func (c configCount) applyDefault(def configCount) configCount {
+	if c.Icon0 == "" {
+		c.Icon0 = def.Icon0
+	}
+
+	if c.Icon == "" {
+		c.Icon = def.Icon
+	}
+
+	if c.TextFormat0 == "" {
+		c.TextFormat0 = def.TextFormat0
+	}
+
+	if c.TextFormat == "" {
+		c.TextFormat = def.TextFormat
+	}
+
+	if c.TooltipFormat0 == "" {
+		c.TooltipFormat0 = def.TooltipFormat0
+	}
+
+	if c.TooltipFormat == "" {
+		c.TooltipFormat = def.TooltipFormat
+	}
+
+	return c
+}
+
+func (c configCount) formatValue(valueStr string) (icon, text, tooltip string, disabled bool) {
+	var textFormat, tooltipFormat string
+
+	if valueStr == "" || valueStr == "0" {
+		icon = c.Icon0
+		textFormat = c.TextFormat0
+		tooltipFormat = c.TooltipFormat0
+	} else {
+		icon = c.Icon
+		textFormat = c.TextFormat
+		tooltipFormat = c.TooltipFormat
+	}
+
+	text = common.ReplaceValue(common.ReplaceValue(textFormat, "icon", icon), "value", valueStr)
+	tooltip = common.ReplaceValue(common.ReplaceValue(tooltipFormat, "icon", icon), "value", valueStr)
+
+	return icon, text, tooltip, false
+}
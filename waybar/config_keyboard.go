@@ -0,0 +1,47 @@
+package waybar
+
+import "github.com/willoma/swaypanion/common"
+
+type configKeyboard struct {
+	ShortNames    map[string]string `yaml:"short_names"`
+	FormatText    string            `yaml:"format_text"`
+	FormatTooltip string            `yaml:"format_tooltip"`
+}
+
+func (c configKeyboard) applyDefault(def configKeyboard) configKeyboard {
+	if len(c.ShortNames) == 0 {
+		c.ShortNames = make(map[string]string, len(def.ShortNames))
+		for k, v := range def.ShortNames {
+			c.ShortNames[k] = v
+		}
+	}
+
+	if c.FormatText == "" {
+		c.FormatText = def.FormatText
+	}
+
+	if c.FormatTooltip == "" {
+		c.FormatTooltip = def.FormatTooltip
+	}
+
+	return c
+}
+
+// formatValue uses the short name of the layout as alt value. The layout
+// name itself is used if there is no configured short name.
+func (c configKeyboard) formatValue(name string, replaces map[string]string) (alt, text, tooltip string, disabled bool) {
+	alt, ok := c.ShortNames[name]
+	if !ok {
+		alt = name
+	}
+
+	text = common.ReplaceValue(common.ReplaceValue(c.FormatText, "short", alt), "name", name)
+	tooltip = common.ReplaceValue(common.ReplaceValue(c.FormatTooltip, "short", alt), "name", name)
+
+	for key, content := range replaces {
+		text = common.ReplaceValue(text, key, content)
+		tooltip = common.ReplaceValue(tooltip, key, content)
+	}
+
+	return alt, text, tooltip, false
+}
@@ -0,0 +1,47 @@
+package waybar
+
+type configWindow struct {
+	configString `yaml:",inline"`
+
+	MaxLength int    `yaml:"max_length"`
+	Ellipsis  string `yaml:"ellipsis"`
+}
+
+func (c configWindow) applyDefault(def configWindow) configWindow {
+	c.configString = c.configString.applyDefault(def.configString)
+
+	if c.MaxLength == 0 {
+		c.MaxLength = def.MaxLength
+	}
+
+	if c.Ellipsis == "" {
+		c.Ellipsis = def.Ellipsis
+	}
+
+	return c
+}
+
+// truncate shortens the text to the maximum length, including the ellipsis.
+// A negative maximum length disables truncation.
+func (c configWindow) truncate(text string) string {
+	runes := []rune(text)
+	if c.MaxLength <= 0 || len(runes) <= c.MaxLength {
+		return text
+	}
+
+	ellipsis := []rune(c.Ellipsis)
+	if len(ellipsis) >= c.MaxLength {
+		return string(runes[:c.MaxLength])
+	}
+
+	return string(runes[:c.MaxLength-len(ellipsis)]) + c.Ellipsis
+}
+
+// iconKey returns the app name if it has an icon, "default" otherwise.
+func (c configWindow) iconKey(app string) string {
+	if _, ok := c.Icons[app]; ok {
+		return app
+	}
+
+	return "default"
+}
@@ -0,0 +1,31 @@
+package waybar
+
+import (
+	"errors"
+	"io"
+
+	"github.com/willoma/swaypanion/socket"
+	socketclient "github.com/willoma/swaypanion/socket/client"
+)
+
+func hidden(w io.Writer, client *socketclient.Client, conf *config) error {
+	if err := client.Send(&socket.Message{
+		Command: "window hidden subscribe",
+	}); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := client.Read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+
+			return nil
+		}
+
+		alt, text, tooltip, disabled := conf.Hidden.formatValue(msg.Value)
+		writeJSON(w, alt, text, tooltip, disabled)
+	}
+}
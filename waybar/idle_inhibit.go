@@ -0,0 +1,42 @@
+package waybar
+
+import (
+	"errors"
+	"io"
+
+	"github.com/willoma/swaypanion/socket"
+	socketclient "github.com/willoma/swaypanion/socket/client"
+)
+
+func idleInhibit(w io.Writer, client *socketclient.Client, conf *config) error {
+	if err := client.Send(&socket.Message{
+		Command: "idle-inhibit subscribe",
+	}); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := client.Read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+
+			return nil
+		}
+
+		replaces := map[string]string{
+			"status": msg.Value,
+			"mode":   "manual",
+		}
+
+		for _, c := range msg.Complement {
+			if c == "Automatic" {
+				replaces["mode"] = "automatic"
+			}
+		}
+
+		alt, text, tooltip, disabled := conf.IdleInhibit.formatValue(msg.Value, replaces)
+		writeJSON(w, alt, text, tooltip, disabled)
+	}
+}
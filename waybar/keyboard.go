@@ -0,0 +1,56 @@
+package waybar
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/willoma/swaypanion/socket"
+	socketclient "github.com/willoma/swaypanion/socket/client"
+)
+
+func keyboard(w io.Writer, client *socketclient.Client, conf *config) error {
+	if err := client.Send(&socket.Message{
+		Command: "keyboard layout subscribe",
+	}); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := client.Read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+
+			return nil
+		}
+
+		replaces := map[string]string{
+			"index":    "",
+			"keyboard": "",
+			"layouts":  "",
+		}
+
+		for _, c := range msg.Complement {
+			splat := strings.SplitN(c, ":", 2)
+			if len(splat) != 2 {
+				continue
+			}
+
+			value := strings.TrimSpace(splat[1])
+
+			switch splat[0] {
+			case "Index":
+				replaces["index"] = value
+			case "Keyboard":
+				replaces["keyboard"] = value
+			case "Layouts":
+				replaces["layouts"] = value
+			}
+		}
+
+		alt, text, tooltip, disabled := conf.Keyboard.formatValue(msg.Value, replaces)
+		writeJSON(w, alt, text, tooltip, disabled)
+	}
+}
@@ -0,0 +1,47 @@
+package waybar
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/willoma/swaypanion/common"
+	"github.com/willoma/swaypanion/socket"
+	socketclient "github.com/willoma/swaypanion/socket/client"
+)
+
+func urgent(w io.Writer, client *socketclient.Client, conf *config) error {
+	if err := client.Send(&socket.Message{
+		Command: "window urgent subscribe",
+	}); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := client.Read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+
+			return nil
+		}
+
+		var apps string
+
+		for _, c := range msg.Complement {
+			if value, ok := strings.CutPrefix(c, "Apps:"); ok {
+				apps = strings.TrimSpace(value)
+			}
+		}
+
+		alt, text, tooltip, disabled := conf.Urgent.formatValue(msg.Value)
+		writeJSON(
+			w,
+			alt,
+			common.ReplaceValue(text, "apps", apps),
+			common.ReplaceValue(tooltip, "apps", apps),
+			disabled,
+		)
+	}
+}
@@ -24,6 +24,16 @@ func Subscribe(w io.Writer, eventType string, configPath string) error {
 		player(w, client, conf)
 	case "volume":
 		volume(w, client, conf)
+	case "hidden":
+		hidden(w, client, conf)
+	case "idle-inhibit":
+		idleInhibit(w, client, conf)
+	case "keyboard":
+		keyboard(w, client, conf)
+	case "urgent":
+		urgent(w, client, conf)
+	case "window":
+		window(w, client, conf)
 	}
 
 	return nil
@@ -34,5 +44,10 @@ func EventTypes() []string {
 		"brightness",
 		"player",
 		"volume",
+		"hidden",
+		"idle-inhibit",
+		"keyboard",
+		"urgent",
+		"window",
 	}
 }
@@ -0,0 +1,81 @@
+package waybar
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/willoma/swaypanion/socket"
+	socketclient "github.com/willoma/swaypanion/socket/client"
+)
+
+func window(w io.Writer, client *socketclient.Client, conf *config) error {
+	if err := client.Send(&socket.Message{
+		Command: "window focused subscribe",
+	}); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := client.Read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+
+			return nil
+		}
+
+		replaces := map[string]string{
+			"id":         msg.Value,
+			"app":        "",
+			"class":      "",
+			"title":      "",
+			"full_title": "",
+			"workspace":  "",
+			"floating":   "",
+			"fullscreen": "",
+		}
+
+		if msg.Value == "none" {
+			replaces["id"] = ""
+		}
+
+		for _, c := range msg.Complement {
+			splat := strings.SplitN(c, ":", 2)
+			if len(splat) != 2 {
+				continue
+			}
+
+			value := strings.TrimSpace(splat[1])
+
+			switch splat[0] {
+			case "App":
+				replaces["app"] = value
+			case "Class":
+				replaces["class"] = value
+			case "Title":
+				replaces["full_title"] = value
+				replaces["title"] = conf.Window.truncate(value)
+			case "Workspace":
+				replaces["workspace"] = value
+			case "Floating":
+				replaces["floating"] = value
+			case "Fullscreen":
+				replaces["fullscreen"] = value
+			}
+		}
+
+		app := replaces["app"]
+		if app == "" {
+			app = replaces["class"]
+		}
+
+		if replaces["id"] == "" {
+			app = "none"
+		}
+
+		alt, text, tooltip, disabled := conf.Window.formatValue(conf.Window.iconKey(app), replaces)
+		writeJSON(w, alt, text, tooltip, disabled)
+	}
+}